@@ -0,0 +1,70 @@
+package dnsleapsecs
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/netip"
+)
+
+// DecodeAddr decodes leap-second information in a netip.Addr.
+// An IPv4-mapped IPv6 address is unmapped first, other IPv6
+// addresses are rejected as invalid.
+func DecodeAddr(a netip.Addr, opts ...Option) (Result, error) {
+	a = a.Unmap()
+	if !a.Is4() {
+		return Result{}, &Error{Code: CodeInvalidAddress, IP: a.String(), Err: errors.New("not an IPv4 address")}
+	}
+	return DecodeBytes(a.As4(), opts...)
+}
+
+// DecodeIP decodes leap-second information in a net.IP. Both the 4 and
+// 16 byte forms of an IPv4 address are accepted, other addresses are
+// rejected as invalid.
+func DecodeIP(ip net.IP, opts ...Option) (Result, error) {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return Result{}, &Error{Code: CodeInvalidAddress, IP: ip.String(), Err: errors.New("not an IPv4 address")}
+	}
+	return DecodeBytes([4]byte(ip4), opts...)
+}
+
+// DecodeBytes decodes leap-second information in the octets of an IPv4
+// address in network order.
+func DecodeBytes(b [4]byte, opts ...Option) (Result, error) {
+	r, err := decodeUint32(binary.BigEndian.Uint32(b[:]), newOptions(opts))
+	return r, annotate(err, net.IP(b[:]).String(), "")
+}
+
+// Addr encodes leap-second information into a netip.Addr.
+// It is the netip counterpart of Encode.
+func (r Result) Addr() (netip.Addr, error) {
+	u, err := EncodeUint32(r)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return netip.AddrFrom4([4]byte{byte(u >> 24), byte(u >> 16), byte(u >> 8), byte(u)}), nil
+}
+
+// DecodeNetAddrs selects and decodes leap-second information from addrs
+// like LookupHost does, for code dealing in net.Addr. Addresses other
+// than IPv4 *net.IPAddr, *net.TCPAddr and *net.UDPAddr are skipped, the
+// selected address is returned in its numeric string form.
+func DecodeNetAddrs(addrs []net.Addr, opts ...Option) (string, Result, error) {
+	var ips []string
+	for _, a := range addrs {
+		var ip net.IP
+		switch a := a.(type) {
+		case *net.IPAddr:
+			ip = a.IP
+		case *net.TCPAddr:
+			ip = a.IP
+		case *net.UDPAddr:
+			ip = a.IP
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			ips = append(ips, ip4.String())
+		}
+	}
+	return selectAddr(ips, newOptions(opts))
+}
@@ -0,0 +1,131 @@
+package dnsleapsecs
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+)
+
+func TestDecodeAddr(t *testing.T) {
+	for _, tv := range TestVectors {
+		t.Run(tv.IP, func(t *testing.T) {
+			r, err := DecodeAddr(netip.MustParseAddr(tv.IP))
+			var e *Error
+			if errors.As(err, &e) && *e != (Error{Code: tv.Err.Code, IP: tv.IP}) {
+				t.Errorf("got %#v, want: %#v", err, tv.Err)
+			}
+			if r != tv.Result {
+				t.Errorf("got %#v, want: %#v", r, tv.Result)
+			}
+		})
+	}
+
+	t.Run("mapped", func(t *testing.T) {
+		r, err := DecodeAddr(netip.MustParseAddr("::ffff:240.3.9.77"))
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if want := (Result{1971, 12, 9, +1, 2}); r != want {
+			t.Errorf("got %#v, want: %#v", r, want)
+		}
+	})
+
+	for _, a := range []netip.Addr{{}, netip.MustParseAddr("2001:db8::1")} {
+		_, err := DecodeAddr(a)
+		var e *Error
+		if !errors.As(err, &e) || e.Code != -1 || e.IP != a.String() {
+			t.Errorf("%v: got %#v, want code -1 for the address", a, err)
+		}
+	}
+}
+
+func TestDecodeIP(t *testing.T) {
+	for _, tv := range TestVectors {
+		want, wantErr := Decode(tv.IP)
+		for _, ip := range []net.IP{net.ParseIP(tv.IP), net.ParseIP(tv.IP).To4()} {
+			r, err := DecodeIP(ip)
+			if r != want || !sameCode(err, wantErr) {
+				t.Errorf("%s (%d bytes): got (%#v, %v), want: (%#v, %v)", tv.IP, len(ip), r, err, want, wantErr)
+			}
+		}
+		r, err := DecodeBytes([4]byte(net.ParseIP(tv.IP).To4()))
+		if r != want || !sameCode(err, wantErr) {
+			t.Errorf("%s: got (%#v, %v), want: (%#v, %v)", tv.IP, r, err, want, wantErr)
+		}
+	}
+
+	for _, ip := range []net.IP{nil, net.ParseIP("2001:db8::1"), {240, 3, 9}} {
+		_, err := DecodeIP(ip)
+		var e *Error
+		if !errors.As(err, &e) || e.Code != -1 || e.IP != ip.String() {
+			t.Errorf("%v: got %#v, want code -1 for the address", ip, err)
+		}
+	}
+}
+
+// sameCode reports whether a and b are both nil or both *Error with the
+// same code.
+func sameCode(a, b error) bool {
+	var ea, eb *Error
+	if !errors.As(a, &ea) || !errors.As(b, &eb) {
+		return a == nil && b == nil
+	}
+	return ea.Code == eb.Code
+}
+
+func TestResultAddr(t *testing.T) {
+	for _, tv := range TestVectors {
+		if tv.Err != nil {
+			continue
+		}
+		t.Run(tv.IP, func(t *testing.T) {
+			a, err := tv.Result.Addr()
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if a.String() != tv.IP {
+				t.Errorf("got %v, want: %v", a, tv.IP)
+			}
+			r, err := DecodeAddr(a)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if r != tv.Result {
+				t.Errorf("got %#v, want: %#v", r, tv.Result)
+			}
+		})
+	}
+
+	_, err := Result{2015, 6, 35, 2, 0}.Addr()
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -20 {
+		t.Errorf("got %#v, want code -20", err)
+	}
+}
+
+func TestDecodeNetAddrs(t *testing.T) {
+	addrs := []net.Addr{
+		&net.IPAddr{IP: net.ParseIP("2001:db8::1")},
+		&net.UnixAddr{Name: "/tmp/sock", Net: "unix"},
+		&net.TCPAddr{IP: net.ParseIP("255.209.76.40"), Port: 53},
+		&net.UDPAddr{IP: net.ParseIP("240.3.9.77"), Port: 53},
+		&net.IPAddr{IP: net.ParseIP("242.18.28.160")},
+	}
+	ip, r, err := DecodeNetAddrs(addrs)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if ip != "240.3.9.77" {
+		t.Errorf("got %q, want: %q", ip, "240.3.9.77")
+	}
+	if want := (Result{1971, 12, 9, +1, 2}); r != want {
+		t.Errorf("got %#v, want: %#v", r, want)
+	}
+
+	_, _, err = DecodeNetAddrs(addrs[:2])
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -11 {
+		t.Errorf("got %#v, want code -11", err)
+	}
+}
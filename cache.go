@@ -0,0 +1,84 @@
+package dnsleapsecs
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache is a decoder remembering outcomes, for pipelines decoding the
+// same addresses over and over. Successfully decoded addresses are kept
+// for a positive TTL, addresses that failed to decode for a separate,
+// typically shorter, negative TTL. It is safe for concurrent use.
+type Cache struct {
+	ttl, negativeTTL time.Duration
+	max              int
+	opt              *options
+	now              func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	r       Result
+	err     error
+	expires time.Time
+}
+
+// NewCache returns a Cache holding at most max addresses, remembering
+// successfully decoded addresses for ttl and failed ones for
+// negativeTTL. A zero TTL disables caching of that kind. Addresses
+// are decoded using opts.
+func NewCache(ttl, negativeTTL time.Duration, max int, opts ...Option) *Cache {
+	return &Cache{
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		max:         max,
+		opt:         newOptions(opts),
+		now:         time.Now,
+		entries:     make(map[string]cacheEntry),
+	}
+}
+
+// Decode is like the package level Decode, but returns the remembered
+// outcome for ip if it hasn't expired.
+func (c *Cache) Decode(ip string) (Result, error) {
+	now := c.now()
+	c.mu.Lock()
+	e, ok := c.entries[ip]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.r, e.err
+	}
+
+	r, err := decode(ip, c.opt)
+	ttl := c.ttl
+	if err != nil {
+		ttl = c.negativeTTL
+	}
+	if ttl > 0 && c.max > 0 {
+		c.mu.Lock()
+		c.store(ip, cacheEntry{r, err, now.Add(ttl)}, now)
+		c.mu.Unlock()
+	}
+	return r, err
+}
+
+// store adds e, making room by evicting expired entries or else the
+// entry expiring first. c.mu must be held.
+func (c *Cache) store(ip string, e cacheEntry, now time.Time) {
+	if _, ok := c.entries[ip]; !ok && len(c.entries) >= c.max {
+		var first string
+		for k, v := range c.entries {
+			if !now.Before(v.expires) {
+				delete(c.entries, k)
+			} else if first == "" || v.expires.Before(c.entries[first].expires) {
+				first = k
+			}
+		}
+		if len(c.entries) >= c.max {
+			delete(c.entries, first)
+		}
+	}
+	c.entries[ip] = e
+}
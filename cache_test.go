@@ -0,0 +1,66 @@
+package dnsleapsecs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)
+	c := NewCache(time.Hour, time.Minute, 2)
+	c.now = func() time.Time { return now }
+
+	r, err := c.Decode("244.23.35.255")
+	if err != nil || r != (Result{2015, 6, 35, +1, 2}) {
+		t.Fatalf("got (%#v, %v)", r, err)
+	}
+	_, err = c.Decode("255.209.76.40")
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -2 {
+		t.Fatalf("got %#v, want code -2", err)
+	}
+	if len(c.entries) != 2 {
+		t.Fatalf("got %d entries, want: 2", len(c.entries))
+	}
+
+	// Cached outcomes are returned as is.
+	c.entries["244.23.35.255"] = cacheEntry{Result{1, 1, 1, 0, 0}, nil, now.Add(time.Hour)}
+	if r, _ := c.Decode("244.23.35.255"); r != (Result{1, 1, 1, 0, 0}) {
+		t.Errorf("got %#v, want cached result", r)
+	}
+
+	// The negative entry expires first and is evicted for a new one.
+	now = now.Add(2 * time.Minute)
+	if _, err := c.Decode("240.3.9.77"); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if _, ok := c.entries["255.209.76.40"]; ok {
+		t.Error("expired negative entry not evicted")
+	}
+	if len(c.entries) != 2 {
+		t.Errorf("got %d entries, want: 2", len(c.entries))
+	}
+
+	// When full without expired entries, the one expiring first goes.
+	if _, err := c.Decode("242.18.28.160"); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if _, ok := c.entries["244.23.35.255"]; ok {
+		t.Error("entry expiring first not evicted")
+	}
+
+	now = now.Add(time.Hour)
+	if r, _ := c.Decode("240.3.9.77"); r != (Result{1971, 12, 9, +1, 2}) {
+		t.Errorf("got %#v", r)
+	}
+}
+
+func TestCacheDisabled(t *testing.T) {
+	c := NewCache(time.Hour, 0, 10)
+	c.Decode("255.209.76.40")
+	c.Decode("244.23.35.255")
+	if len(c.entries) != 1 {
+		t.Errorf("got %d entries, want: 1", len(c.entries))
+	}
+}
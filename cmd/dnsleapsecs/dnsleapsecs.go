@@ -2,14 +2,69 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
 
 	"github.com/dwlnetnl/dnsleapsecs"
 )
 
+var format = flag.String("format", "", "print the published announcement using a Go `template`, "+
+	"executed against the Result and its derived values, instead of the human log")
+
+var jsonFlag = flag.Bool("json", false, "print the published announcement as a JSON object instead of the human log, "+
+	"errors are printed as JSON to stderr")
+
+var watchFlag = flag.Duration("watch", 0, "keep querying the published announcement every `interval`, "+
+	"logging it when it changes, until interrupted")
+
 func main() {
 	log.SetFlags(0)
+	flag.Parse()
+	if err := checkFlags(*format, *jsonFlag, *watchFlag); err != nil {
+		fmt.Fprintln(flag.CommandLine.Output(), err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *format != "" {
+		tmpl, err := template.New("format").Parse(*format)
+		if err != nil {
+			log.Fatalf("invalid format: %v", err)
+		}
+		ip, r, err := dnsleapsecs.Fetch(context.Background())
+		if err != nil {
+			log.Fatalf("failed with error: %v", err)
+		}
+		if err := execute(os.Stdout, tmpl, ip, r, time.Now()); err != nil {
+			log.Fatalf("failed with error: %v", err)
+		}
+		return
+	}
+	if *jsonFlag {
+		ip, r, err := dnsleapsecs.Fetch(context.Background())
+		if !writeJSON(os.Stdout, os.Stderr, dnsleapsecs.DefaultHost, ip, r, err) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *watchFlag > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		watch(ctx, dnsleapsecs.NewMonitor(net.DefaultResolver, *watchFlag), log.Printf)
+		return
+	}
 
 	log.Println("Checking test-vectors:")
 	log.Println()
@@ -39,16 +94,131 @@ func main() {
 	log.Println()
 	log.Printf("   IP: %-15s  Error: %2d  Year: %4d  Month %2d  dTAI: %3d  Delta:  %2d",
 		ip, 0, r.Year, r.Month, r.DTAI, r.Delta)
+	if u, err := r.Packed(); err == nil {
+		log.Printf("   Packed: 0x%08x", u)
+	}
 	log.Println()
 
 	log.Println("That means:")
 	log.Println()
 	log.Printf("   Information is valid until end of UTC-month %d of year %d",
 		r.Month, r.Year)
-	log.Printf("   After that month: UTC = TAI - %d seconds", r.DTAI+r.Delta)
+	log.Printf("   After that month: UTC = TAI - %d seconds", r.OffsetAfterHorizon())
 	log.Printf("   Until then:       UTC = TAI - %d seconds", r.DTAI)
 }
 
+// templateData is what the format template is executed against: the
+// address, the Result fields and methods (.DTAI, .HumanHorizon) and
+// the values derived at the time of fetching (.Offset, .Horizon).
+type templateData struct {
+	IP string
+	dnsleapsecs.Announcement
+}
+
+func execute(w io.Writer, tmpl *template.Template, ip string, r dnsleapsecs.Result, now time.Time) error {
+	if err := tmpl.Execute(w, templateData{ip, r.Describe(now)}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// checkFlags reports an error if more than one of the output modes
+// -format, -json and -watch is selected.
+func checkFlags(format string, json bool, watch time.Duration) error {
+	var modes []string
+	if format != "" {
+		modes = append(modes, "-format")
+	}
+	if json {
+		modes = append(modes, "-json")
+	}
+	if watch != 0 {
+		modes = append(modes, "-watch")
+	}
+	if len(modes) > 1 {
+		return fmt.Errorf("flags %s can't be combined", strings.Join(modes, " and "))
+	}
+	if watch < 0 {
+		return fmt.Errorf("invalid -watch interval %v", watch)
+	}
+	return nil
+}
+
+// watch runs m until ctx is done, logging the first announcement and
+// every change to it, failed lookups and missed publication windows.
+func watch(ctx context.Context, m *dnsleapsecs.Monitor, logf func(format string, v ...interface{})) {
+	done := make(chan struct{})
+	go func() {
+		m.Run(ctx)
+		close(done)
+	}()
+	var last *dnsleapsecs.Result
+	report := func(e dnsleapsecs.Event) {
+		switch e.Kind {
+		case dnsleapsecs.EventAnnouncement:
+			if last == nil {
+				logf("announcement: %v", e.Result)
+			} else {
+				logf("announcement: %v, changed from %v", e.Result, *last)
+			}
+			r := e.Result
+			last = &r
+		default:
+			if ctx.Err() == nil {
+				logf("%v: %v", e.Kind, e.Err)
+			}
+		}
+	}
+	for {
+		select {
+		case e := <-m.Events():
+			report(e)
+		case <-done:
+			for {
+				select {
+				case e := <-m.Events():
+					report(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// jsonOutput is what -json prints for an announcement.
+type jsonOutput struct {
+	Host   string             `json:"host"`
+	IP     string             `json:"ip"`
+	Result dnsleapsecs.Result `json:"result"`
+}
+
+// jsonError is what -json prints for an error.
+type jsonError struct {
+	Error string `json:"error"`
+	Code  int    `json:"code,omitempty"`
+}
+
+// writeJSON writes the announcement r published at ip for host as JSON
+// to stdout, or err as JSON to stderr if it's non-nil. It reports
+// whether the announcement was written.
+func writeJSON(stdout, stderr io.Writer, host, ip string, r dnsleapsecs.Result, err error) bool {
+	if err != nil {
+		out := jsonError{Error: err.Error()}
+		var e *dnsleapsecs.Error
+		if errors.As(err, &e) {
+			out.Code = e.Code
+		}
+		json.NewEncoder(stderr).Encode(out)
+		return false
+	}
+	if err := json.NewEncoder(stdout).Encode(jsonOutput{host, ip, r}); err != nil {
+		return writeJSON(stdout, stderr, host, ip, r, err)
+	}
+	return true
+}
+
 type testVector struct {
 	IP     string
 	Result dnsleapsecs.Result
@@ -75,7 +245,7 @@ var assertName = [...]string{
 func assertDecode(tv testVector) (dnsleapsecs.Result, *dnsleapsecs.Error, int) {
 	r, err := dnsleapsecs.Decode(tv.IP)
 	var e *dnsleapsecs.Error
-	if errors.As(err, &e) && *e != *tv.Err {
+	if errors.As(err, &e) && e.Code != tv.Err.Code {
 		return dnsleapsecs.Result{}, e, assertError
 	}
 	if r.Year != tv.Result.Year {
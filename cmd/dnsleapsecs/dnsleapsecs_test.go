@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/dwlnetnl/dnsleapsecs"
+)
+
+func TestExecute(t *testing.T) {
+	r, err := dnsleapsecs.Decode("244.23.35.255")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC)
+	tmpl := template.Must(template.New("format").Parse(
+		"{{.IP}} {{.HumanHorizon}} dTAI={{.DTAI}} offset={{.Offset}} pending={{.Pending}}"))
+	var b bytes.Buffer
+	if err := execute(&b, tmpl, "244.23.35.255", r, now); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if want := "244.23.35.255 June 2015 dTAI=35 offset=36 pending=false\n"; b.String() != want {
+		t.Errorf("got %q, want: %q", b.String(), want)
+	}
+
+	b.Reset()
+	tmpl = template.Must(template.New("format").Parse("{{.IP}} {{.NoSuchField}}"))
+	if err := execute(&b, tmpl, "244.23.35.255", r, now); err == nil {
+		t.Errorf("got no error, output %q", b.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	r, err := dnsleapsecs.Decode("244.23.35.255")
+	if !writeJSON(&stdout, &stderr, "leapsecond.utcd.org", "244.23.35.255", r, err) {
+		t.Fatalf("got error: %s", &stderr)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("got stderr: %s", &stderr)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"host": "leapsecond.utcd.org",
+		"ip":   "244.23.35.255",
+		"result": map[string]interface{}{
+			"year":       2015.0,
+			"month":      6.0,
+			"dtai":       35.0,
+			"delta":      1.0,
+			"utc_offset": -35.0,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want: %v", got, want)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	r, err = dnsleapsecs.Decode("255.209.76.40")
+	if writeJSON(&stdout, &stderr, "leapsecond.utcd.org", "255.209.76.40", r, err) {
+		t.Fatal("got no error")
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("got stdout: %s", &stdout)
+	}
+	var e jsonError
+	if err := json.Unmarshal(stderr.Bytes(), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Code != -2 || e.Error == "" {
+		t.Errorf("got %#v, want code -2", e)
+	}
+}
+
+// sequenceResolver resolves to the encodings of results in turn, a zero
+// Result fails to resolve. After the last one it calls done.
+type sequenceResolver struct {
+	results []dnsleapsecs.Result
+	n       int
+	done    func()
+}
+
+func (sr *sequenceResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r := sr.results[sr.n]
+	sr.n++
+	if sr.n == len(sr.results) {
+		sr.done()
+	}
+	if r == (dnsleapsecs.Result{}) {
+		return nil, errors.New("some lookup error")
+	}
+	ip, err := dnsleapsecs.Encode(r)
+	return []string{ip}, err
+}
+
+func TestWatch(t *testing.T) {
+	// Far-off horizons keep the monitor from reporting missed windows.
+	r1 := dnsleapsecs.Result{Year: 2100, Month: 6, DTAI: 37}
+	r2 := dnsleapsecs.Result{Year: 2100, Month: 12, DTAI: 37}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sr := &sequenceResolver{results: []dnsleapsecs.Result{r1, r1, {}, r2, r2}, done: cancel}
+	var logged []string
+	logf := func(format string, v ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, v...))
+	}
+
+	watch(ctx, dnsleapsecs.NewMonitor(sr, time.Millisecond), logf)
+	if sr.n != len(sr.results) {
+		t.Errorf("got %d lookups, want: %d", sr.n, len(sr.results))
+	}
+	want := []string{
+		"announcement: 2100-06 dTAI=37 (UTC=TAI-37s), no change",
+		"error: lookup failed",
+		"announcement: 2100-12 dTAI=37 (UTC=TAI-37s), no change, changed from 2100-06",
+	}
+	if len(logged) != len(want) {
+		t.Fatalf("got %d lines, want: %d\n%s", len(logged), len(want), strings.Join(logged, "\n"))
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(logged[i], w) {
+			t.Errorf("line %d: got %q, want prefix %q", i, logged[i], w)
+		}
+	}
+}
+
+func TestCheckFlags(t *testing.T) {
+	tests := []struct {
+		format string
+		json   bool
+		watch  time.Duration
+		ok     bool
+	}{
+		{"", false, 0, true},
+		{"{{.DTAI}}", false, 0, true},
+		{"", true, 0, true},
+		{"", false, time.Hour, true},
+		{"{{.DTAI}}", true, 0, false},
+		{"", true, time.Hour, false},
+		{"{{.DTAI}}", false, time.Hour, false},
+		{"{{.DTAI}}", true, time.Hour, false},
+		{"", false, -time.Hour, false},
+	}
+	for _, tt := range tests {
+		if err := checkFlags(tt.format, tt.json, tt.watch); (err == nil) != tt.ok {
+			t.Errorf("(%q, %t, %v): got %v, want ok: %t", tt.format, tt.json, tt.watch, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package dnsleapsecs
+
+import "errors"
+
+// Compact packs r into 3 bytes, leaving out the class E nibble and the
+// CRC-8 of the encoded form. The 20 meaningful bits are right aligned
+// in big endian order:
+//
+//	  2                   1                   0
+//	3 2 1 0 9 8 7 6 5 4 3 2 1 0 9 8 7 6 5 4 3 2 1 0
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//	|0 0 0 0|        month        | d |   dTAI      |
+//	+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+//
+// The fields are those of the encoded form. An invalid Result, see
+// Validate, packs into the zero value.
+func (r Result) Compact() [3]byte {
+	u, err := EncodeUint32(r)
+	if err != nil {
+		return [3]byte{}
+	}
+	u = (u >> 8) & 0xfffff
+	return [3]byte{byte(u >> 16), byte(u >> 8), byte(u)}
+}
+
+// DecodeCompact decodes leap-second information packed by Compact. As
+// the compact form has no CRC octet, it's computed using the verifier
+// of the CRC option, if any, so that the check passes.
+func DecodeCompact(b [3]byte, opts ...Option) (Result, error) {
+	if b[0]>>4 != 0 {
+		return Result{}, &Error{Code: CodeInvalidAddress, Err: errors.New("compact form has top nibble set")}
+	}
+	opt := newOptions(opts)
+	u := (0xf<<20 | uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])) << 8
+	u |= opt.crcVerifier().Compute(u & (1<<28 - 1))
+	return decodeUint32(u, opt)
+}
@@ -0,0 +1,51 @@
+package dnsleapsecs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	for _, tv := range TestVectors {
+		if tv.Err != nil {
+			continue
+		}
+		t.Run(tv.IP, func(t *testing.T) {
+			b := tv.Result.Compact()
+			r, err := DecodeCompact(b)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if r != tv.Result {
+				t.Errorf("got %#v, want: %#v", r, tv.Result)
+			}
+		})
+	}
+
+	// month 0x20b, d 2, dtai 0x23
+	if got, want := (Result{2015, 6, 35, +1, 2}).Compact(), [3]byte{0x04, 0x17, 0x23}; got != want {
+		t.Errorf("got %x, want: %x", got, want)
+	}
+	if got := (Result{2015, 6, 35, 2, 0}).Compact(); got != [3]byte{} {
+		t.Errorf("got %x, want zero value", got)
+	}
+
+	_, err := DecodeCompact([3]byte{0x14, 0x17, 0x23})
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -1 {
+		t.Errorf("got %#v, want code -1", err)
+	}
+	_, err = DecodeCompact([3]byte{0x04, 0x17, 0x80 | 0x23})
+	if !errors.As(err, &e) || e.Code != -3 {
+		t.Errorf("got %#v, want code -3", err)
+	}
+
+	// The CRC octet is computed using the configured verifier.
+	r, err := DecodeCompact([3]byte{0x04, 0x17, 0x23}, CRC(xorVerifier{}))
+	if err != nil {
+		t.Fatalf("CRC: got error: %v", err)
+	}
+	if want := (Result{2015, 6, 35, +1, 2}); r != want {
+		t.Errorf("CRC: got %#v, want: %#v", r, want)
+	}
+}
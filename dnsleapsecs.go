@@ -8,8 +8,10 @@ package dnsleapsecs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"time"
 )
 
 /*-
@@ -89,9 +91,16 @@ type Resolver interface {
 	LookupHost(ctx context.Context, host string) (addrs []string, err error)
 }
 
+// ResolverTTL is a Resolver that also reports the time-to-live of the
+// records it resolves.
+type ResolverTTL interface {
+	Resolver
+	LookupHostTTL(ctx context.Context, host string) (addrs []string, ttl time.Duration, err error)
+}
+
 // Result contains leap-second information.
 type Result struct {
-	// Announced horizon.
+	// Announced horizon, Month is 1-based (January is 1).
 	Year, Month int
 
 	// DTAI is what to subtract from TAI to get UTC until that month ends.
@@ -99,79 +108,414 @@ type Result struct {
 
 	// Delta is what needs to be applied to DTAI at the end of that month.
 	Delta int
+
+	// Action is the raw 'd' field Delta is interpreted from: 0 for no
+	// change, 1 for subtract one and 2 for add one to dTAI. Decode sets
+	// it along with Delta. When encoding, a zero Action is derived from
+	// Delta, otherwise they must agree.
+	Action int
 }
 
 // Error is the error type returned.
 type Error struct {
-	Code int
+	Code int    // one of the Code constants
+	IP   string // offending address, if any
+	Host string // host looked up, if any
 	Err  error
 }
 
 func (e *Error) Unwrap() error { return e.Err }
+
+// Is reports whether target is an *Error with the same code, so that
+// errors.Is(err, ErrInvalidChecksum) holds for any invalid checksum.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
 func (e *Error) Error() string {
 	s := errorCodeReason[e.Code]
+	if e.IP != "" {
+		s += " (" + e.IP + ")"
+	}
 	if e.Err != nil {
 		s += ": " + e.Err.Error()
 	}
 	return s
 }
 
+// annotate returns err with its IP and Host set to ip and host when it's
+// an *Error and they're not set yet. A copy is returned, err itself is
+// left untouched as it may be shared.
+func annotate(err error, ip, host string) error {
+	e, ok := err.(*Error)
+	if !ok {
+		return err
+	}
+	c := *e
+	if c.IP == "" {
+		c.IP = ip
+	}
+	if c.Host == "" {
+		c.Host = host
+	}
+	return &c
+}
+
+// DNSError returns the *net.DNSError wrapped by e, if any, to inspect
+// IsTimeout or IsTemporary in deciding whether to retry a lookup.
+func (e *Error) DNSError() (*net.DNSError, bool) {
+	var de *net.DNSError
+	ok := errors.As(e.Err, &de)
+	return de, ok
+}
+
+// Error codes.
+const (
+	CodeInvalidAddress         = -1
+	CodeInvalidChecksum        = -2
+	CodeInvalidAction          = -3
+	CodeInvalidMonth           = -4
+	CodeUnexpectedOffsetChange = -5
+	CodeMalformedAddress       = -6
+	CodeLookupFailed           = -10
+	CodeEmptyResponse          = -11
+	CodeStaleAnnouncement      = -12
+	CodeTTLOutOfBounds         = -13
+	CodeInvalidResult          = -20
+)
+
+// Sentinel errors for use with errors.Is, matching any *Error with the
+// same code.
+var (
+	ErrInvalidAddress         = &Error{Code: CodeInvalidAddress}
+	ErrInvalidChecksum        = &Error{Code: CodeInvalidChecksum}
+	ErrInvalidAction          = &Error{Code: CodeInvalidAction}
+	ErrInvalidMonth           = &Error{Code: CodeInvalidMonth}
+	ErrUnexpectedOffsetChange = &Error{Code: CodeUnexpectedOffsetChange}
+	ErrMalformedAddress       = &Error{Code: CodeMalformedAddress}
+	ErrLookupFailed           = &Error{Code: CodeLookupFailed}
+	ErrEmptyResponse          = &Error{Code: CodeEmptyResponse}
+	ErrStaleAnnouncement      = &Error{Code: CodeStaleAnnouncement}
+	ErrTTLOutOfBounds         = &Error{Code: CodeTTLOutOfBounds}
+	ErrInvalidResult          = &Error{Code: CodeInvalidResult}
+)
+
 var errorCodeReason = map[int]string{
-	-1:  "invalid address",
-	-2:  "invalid checksum",
-	-3:  "invalid action",
-	-10: "lookup failed",
-	-11: "empty response",
+	CodeInvalidAddress:         "invalid address",
+	CodeInvalidChecksum:        "invalid checksum",
+	CodeInvalidAction:          "invalid action",
+	CodeInvalidMonth:           "invalid month",
+	CodeUnexpectedOffsetChange: "unexpected offset change",
+	CodeMalformedAddress:       "malformed address",
+	CodeLookupFailed:           "lookup failed",
+	CodeEmptyResponse:          "empty response",
+	CodeStaleAnnouncement:      "stale announcement",
+	CodeTTLOutOfBounds:         "ttl out of bounds",
+	CodeInvalidResult:          "invalid result",
 }
 
+// DefaultHost is the host record used by Lookup, LookupTTL and the
+// Fetch functions. It may be changed to redirect lookups process-wide,
+// but it isn't guarded: set it during initialization, before any lookup
+// takes place.
+var DefaultHost = "leapsecond.utcd.org"
+
+// defaultResolver is the resolver used by Fetch.
+var defaultResolver Resolver = net.DefaultResolver
+
+// DefaultFetchTimeout is the timeout the Fetch functions apply when ctx
+// has no deadline.
+const DefaultFetchTimeout = 5 * time.Second
+
 // Fetch fetches and decodes leap-second information,
-// using net.DefaultResolver and "leapsecond.utcd.org".
+// using net.DefaultResolver and DefaultHost.
 // Additionally the raw IPv4 address is returned as well.
 //
 // In the unlikely case there is more than a single result,
 // first successfully parsed address is used.
-func Fetch(ctx context.Context) (string, Result, error) {
-	return Lookup(ctx, net.DefaultResolver)
+//
+// When ctx has no deadline, DefaultFetchTimeout applies.
+func Fetch(ctx context.Context, opts ...Option) (string, Result, error) {
+	ctx, cancel := fetchContext(ctx)
+	defer cancel()
+	return Lookup(ctx, defaultResolver, opts...)
+}
+
+// FetchTimeout is like Fetch, but gives up after d. A deadline of ctx
+// that is sooner is respected. On timeout the lookup failed (-10) error
+// wrapping context.DeadlineExceeded is returned.
+func FetchTimeout(ctx context.Context, d time.Duration, opts ...Option) (string, Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	return Lookup(ctx, defaultResolver, opts...)
+}
+
+// fetchContext returns ctx with DefaultFetchTimeout applied when it has
+// no deadline.
+func fetchContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		panic("context is nil")
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, DefaultFetchTimeout)
+}
+
+// FetchHost is like Fetch, but looks up host instead of DefaultHost,
+// as for an internal mirror of the zone.
+func FetchHost(ctx context.Context, host string, opts ...Option) (string, Result, error) {
+	ctx, cancel := fetchContext(ctx)
+	defer cancel()
+	return LookupHost(ctx, defaultResolver, host, opts...)
+}
+
+// FetchTTL is like Fetch, but additionally returns the time-to-live of
+// the record to know when to fetch again. The TTL is zero when it is
+// unavailable, which is the case for net.DefaultResolver as it doesn't
+// implement ResolverTTL.
+func FetchTTL(ctx context.Context, opts ...Option) (string, Result, time.Duration, error) {
+	ctx, cancel := fetchContext(ctx)
+	defer cancel()
+	return LookupTTL(ctx, defaultResolver, opts...)
 }
 
 // Lookup fetches and parses the leap-second information,
-// using the "leapsecond.utcd.org" host record. Additionally
+// using the DefaultHost host record. Additionally
 // the raw IPv4 address is returned as well.
 //
 // In the unlikely case there is more than a single result,
 // first successfully parsed address is used.
-func Lookup(ctx context.Context, r Resolver) (string, Result, error) {
-	return LookupHost(ctx, r, "leapsecond.utcd.org")
+func Lookup(ctx context.Context, r Resolver, opts ...Option) (string, Result, error) {
+	return LookupHost(ctx, r, DefaultHost, opts...)
+}
+
+// LookupTTL is like Lookup, but additionally returns the time-to-live
+// of the record. The TTL is zero if r doesn't implement ResolverTTL.
+func LookupTTL(ctx context.Context, r Resolver, opts ...Option) (string, Result, time.Duration, error) {
+	return LookupHostTTL(ctx, r, DefaultHost, opts...)
 }
 
 // LookupHost fetches and parses the leap-second information.
 // Additionally the raw IPv4 address is returned as well.
 //
 // In the unlikely case there is more than a single result,
-// first successfully parsed address is used.
-func LookupHost(ctx context.Context, r Resolver, host string) (string, Result, error) {
+// first successfully parsed address is used. Addresses failing
+// to decode are skipped, including those with an invalid action
+// unless the StrictAction option is used, ReportInvalidAction lists
+// them. If none decodes, the error of the last address is returned.
+func LookupHost(ctx context.Context, r Resolver, host string, opts ...Option) (string, Result, error) {
+	ip, dr, _, err := lookupHost(ctx, r, host, newOptions(opts))
+	return ip, dr, err
+}
+
+// LookupHostTTL is like LookupHost, but additionally returns the
+// time-to-live of the record. The TTL is zero if r doesn't implement
+// ResolverTTL.
+func LookupHostTTL(ctx context.Context, r Resolver, host string, opts ...Option) (string, Result, time.Duration, error) {
+	return lookupHost(ctx, r, host, newOptions(opts))
+}
+
+// LookupSeq is like LookupHost, but tries hosts in order and returns
+// the first valid result. If every host fails, the errors of all hosts
+// are returned joined, each prefixed by its host.
+func LookupSeq(ctx context.Context, r Resolver, hosts ...string) (string, Result, error) {
+	if len(hosts) == 0 {
+		panic("no hosts")
+	}
+	var errs []error
+	for _, host := range hosts {
+		ip, dr, err := LookupHost(ctx, r, host)
+		if err == nil {
+			return ip, dr, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", host, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return "", Result{}, errors.Join(errs...)
+}
+
+// LookupHosts is like LookupSeq, but looks up all hosts concurrently and
+// returns the first valid result to arrive, canceling the other lookups.
+// If every host fails, the errors of all hosts are returned joined in the
+// order of hosts, each prefixed by its host.
+func LookupHosts(ctx context.Context, r Resolver, hosts []string) (string, Result, error) {
+	if len(hosts) == 0 {
+		panic("no hosts")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		i   int
+		ip  string
+		r   Result
+		err error
+	}
+	ch := make(chan result, len(hosts))
+	for i, host := range hosts {
+		go func(i int, host string) {
+			ip, dr, err := LookupHost(ctx, r, host)
+			ch <- result{i, ip, dr, err}
+		}(i, host)
+	}
+	errs := make([]error, len(hosts))
+	for range hosts {
+		res := <-ch
+		if res.err == nil {
+			return res.ip, res.r, nil
+		}
+		errs[res.i] = fmt.Errorf("%s: %w", hosts[res.i], res.err)
+	}
+	return "", Result{}, errors.Join(errs...)
+}
+
+// LookupHostChecked is like LookupHost, but additionally reports whether
+// another address decoded successfully into a different Result, which
+// flags an inconsistent or poisoned response.
+func LookupHostChecked(ctx context.Context, r Resolver, host string, opts ...Option) (string, Result, bool, error) {
+	opt := newOptions(opts)
+	addrs, _, err := lookupAll(ctx, r, host, opt)
+	if err != nil {
+		return "", Result{}, false, err
+	}
+	ip, dr, err := selectDecoded(addrs, opt)
+	if err != nil {
+		return ip, dr, false, annotate(err, "", host)
+	}
+	for _, a := range addrs {
+		if a.Err == nil && !a.Result.Equal(dr) {
+			return ip, dr, true, nil
+		}
+	}
+	return ip, dr, false, nil
+}
+
+func lookupHost(ctx context.Context, r Resolver, host string, opt *options) (string, Result, time.Duration, error) {
+	addrs, ttl, err := lookupAll(ctx, r, host, opt)
+	if err != nil {
+		return "", Result{}, 0, err
+	}
+	ip, dr, err := selectDecoded(addrs, opt)
+	if err != nil {
+		return ip, dr, 0, annotate(err, "", host)
+	}
+	return ip, dr, ttl, nil
+}
+
+// DecodedAddr is an address returned by a lookup with the outcome of
+// decoding it.
+type DecodedAddr struct {
+	IP     string
+	Result Result
+	Err    error
+}
+
+// LookupAll is like LookupHost, but returns every distinct address the
+// resolver returned in order, each with its Result or decode error, for
+// diagnostics. An empty response returns the empty response (-11)
+// error and a failed lookup the lookup failed (-10) error.
+func LookupAll(ctx context.Context, r Resolver, host string, opts ...Option) ([]DecodedAddr, error) {
+	addrs, _, err := lookupAll(ctx, r, host, newOptions(opts))
+	return addrs, err
+}
+
+func lookupAll(ctx context.Context, r Resolver, host string, opt *options) ([]DecodedAddr, time.Duration, error) {
+	ips, ttl, err := resolve(ctx, r, host)
+	if err != nil {
+		return nil, 0, annotate(err, "", host)
+	}
+	if err := opt.checkTTL(r, ttl); err != nil {
+		return nil, 0, annotate(err, "", host)
+	}
+	if len(ips) == 0 {
+		return nil, 0, annotate(opt.emptyResponse(), "", host)
+	}
+	return decodeAddrs(dedup(ips), opt), ttl, nil
+}
+
+// resolve looks up the addresses of host, with their TTL when r
+// implements ResolverTTL.
+func resolve(ctx context.Context, r Resolver, host string) ([]string, time.Duration, error) {
 	if ctx == nil {
 		panic("context is nil")
 	}
 	if r == nil {
 		panic("resolver is nil")
 	}
-	ips, err := r.LookupHost(ctx, host)
+	var ips []string
+	var ttl time.Duration
+	var err error
+	if rt, ok := r.(ResolverTTL); ok {
+		ips, ttl, err = rt.LookupHostTTL(ctx, host)
+	} else {
+		ips, err = r.LookupHost(ctx, host)
+	}
 	if err != nil {
-		return "", Result{}, &Error{Code: -10, Err: err}
+		return nil, 0, &Error{Code: CodeLookupFailed, Err: err}
 	}
+	return ips, ttl, nil
+}
+
+// selectAddr decodes ips in order and returns the first successfully
+// decoded address, or the last error.
+func selectAddr(ips []string, opt *options) (string, Result, error) {
 	if len(ips) == 0 {
-		return "", Result{}, &Error{Code: -11}
+		return "", Result{}, opt.emptyResponse()
 	}
-	var ip string
-	var dr Result
-	for _, ip = range ips {
-		dr, err = Decode(ip)
-		if err == nil {
+	return selectDecoded(decodeAddrs(dedup(ips), opt), opt)
+}
+
+// decodeAddrs decodes each of ips.
+func decodeAddrs(ips []string, opt *options) []DecodedAddr {
+	addrs := make([]DecodedAddr, len(ips))
+	for i, ip := range ips {
+		r, err := decode(ip, opt)
+		addrs[i] = DecodedAddr{IP: ip, Result: r, Err: err}
+	}
+	return addrs
+}
+
+// selectDecoded returns the first successfully decoded of addrs, or the
+// last error. addrs must not repeat addresses, see dedup.
+func selectDecoded(addrs []DecodedAddr, opt *options) (string, Result, error) {
+	if opt.invalidAction != nil {
+		for _, a := range addrs {
+			if e, ok := a.Err.(*Error); ok && e.Code == CodeInvalidAction {
+				*opt.invalidAction = append(*opt.invalidAction, a.IP)
+			}
+		}
+	}
+	var a DecodedAddr
+	for _, a = range addrs {
+		if a.Err == nil {
+			break
+		}
+		if e, ok := a.Err.(*Error); ok && e.Code == CodeInvalidAction && (opt.strictAction || opt.rawAction) {
 			break
 		}
 	}
-	return ip, dr, err
+	return a.IP, a.Result, a.Err
+}
+
+// dedup removes repeated addresses from ips, preserving order.
+// Duplicates carry no information, resolvers sometimes return them.
+func dedup(ips []string) []string {
+	if len(ips) < 2 {
+		return ips
+	}
+	seen := make(map[string]bool, len(ips))
+	out := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		if !seen[ip] {
+			seen[ip] = true
+			out = append(out, ip)
+		}
+	}
+	return out
 }
 
 // Decode decodes leap-second information in a numeric IPv4 string
@@ -180,27 +524,85 @@ func LookupHost(ctx context.Context, r Resolver, host string) (string, Result, e
 // year and month is the announced horizon.
 // dtai is what you subtract from TAI to get UTC until that month ends.
 // delta is what you do to dtai at the end of that month.
-func Decode(ip string) (Result, error) {
-	// Convert to 32 bit integer
-	var o1, o2, o3, o4 uint32
-	n, err := fmt.Sscanf(ip, "%d.%d.%d.%d", &o1, &o2, &o3, &o4)
-	if n != 4 {
-		return Result{}, &Error{Code: -1, Err: err}
+func Decode(ip string, opts ...Option) (Result, error) {
+	return decode(ip, newOptions(opts))
+}
+
+// DecodeRaw is like Decode, but additionally returns the 32 bit word ip
+// parses into, before it's split into fields. Unlike with DecodeFull the
+// word is returned whenever ip parses, also when a check fails, so that
+// a bad payload can be logged.
+func DecodeRaw(ip string, opts ...Option) (Result, uint32, error) {
+	u, err := parseIPv4(ip)
+	if err != nil {
+		return Result{}, 0, &Error{Code: CodeMalformedAddress, IP: ip, Err: err}
 	}
+	r, err := decodeUint32(u, newOptions(opts))
+	return r, u, annotate(err, ip, "")
+}
 
-	u := o1 << 24
-	u |= o2 << 16
-	u |= o3 << 8
-	u |= o4
+// DecodeFull is like Decode, but additionally returns the address in
+// network order bytes and as the packed 32 bit word, for logging and
+// re-encoding without parsing again. The bytes and word are only
+// meaningful when err is nil.
+func DecodeFull(ip string, opts ...Option) (Result, [4]byte, uint32, error) {
+	u, err := parseIPv4(ip)
+	if err != nil {
+		return Result{}, [4]byte{}, 0, &Error{Code: CodeMalformedAddress, IP: ip, Err: err}
+	}
+	r, err := decodeUint32(u, newOptions(opts))
+	if err != nil {
+		return r, [4]byte{}, 0, annotate(err, ip, "")
+	}
+	return r, [4]byte{byte(u >> 24), byte(u >> 16), byte(u >> 8), byte(u)}, u, nil
+}
+
+// Verify decodes ip and compares the outcome with want. A decode error
+// is returned as is, a mismatch is reported naming the first field that
+// differs.
+func Verify(ip string, want Result) error {
+	r, err := Decode(ip)
+	if err != nil {
+		return err
+	}
+	switch {
+	case r.Year != want.Year:
+		return fmt.Errorf("%s: year is %d, want %d", ip, r.Year, want.Year)
+	case r.Month != want.Month:
+		return fmt.Errorf("%s: month is %d, want %d", ip, r.Month, want.Month)
+	case r.DTAI != want.DTAI:
+		return fmt.Errorf("%s: dtai is %d, want %d", ip, r.DTAI, want.DTAI)
+	case r.Delta != want.Delta:
+		return fmt.Errorf("%s: delta is %d, want %d", ip, r.Delta, want.Delta)
+	}
+	return nil
+}
 
+func decode(ip string, opt *options) (Result, error) {
+	// Convert to 32 bit integer
+	u, err := parseIPv4(ip)
+	if err != nil {
+		return Result{}, &Error{Code: CodeMalformedAddress, IP: ip, Err: err}
+	}
+	r, err := decodeUint32(u, opt)
+	return r, annotate(err, ip, "")
+}
+
+// decodeUint32 decodes leap-second information in a network-order
+// 32 bit integer.
+func decodeUint32(u uint32, opt *options) (Result, error) {
 	// Check & remove class E
 	if (u >> 28) != 0xf {
-		return Result{}, &Error{Code: -1}
+		return Result{}, &Error{Code: CodeInvalidAddress}
 	}
 
 	// Check & remove CRC8
-	if crc8(u) != 0x80 {
-		return Result{}, &Error{Code: -2}
+	var err error
+	if !opt.crcVerifier().Verify(u & (1<<28 - 1)) {
+		err = &Error{Code: CodeInvalidChecksum}
+		if !opt.tentative {
+			return Result{}, err
+		}
 	}
 	u >>= 8
 
@@ -211,18 +613,32 @@ func Decode(ip string) (Result, error) {
 	d := u & 3
 	u >>= 2
 
-	mn := (u & 0x7ff) + 10
+	m := u & 0x7ff
+	mn := m + 10
 
 	// Error checks
-	if d == 3 {
-		return Result{}, &Error{Code: -3}
+	valid := err == nil
+	if d == 3 && valid {
+		err = &Error{Code: CodeInvalidAction}
+		if !opt.rawAction {
+			return Result{}, err
+		}
+	}
+	// Months count from December 1971, a zero month field would decode
+	// to November 1971 preceding the epoch.
+	if m == 0 && valid {
+		return Result{}, &Error{Code: CodeInvalidMonth}
 	}
 
 	// Convert to return values
 	r := Result{
-		Year:  1971 + (int(mn) / 12),
-		Month: 1 + (int(mn) % 12),
-		DTAI:  int(o),
+		Year:   1971 + (int(mn) / 12),
+		Month:  1 + (int(mn) % 12),
+		DTAI:   int(o),
+		Action: int(d),
+	}
+	if opt.signedDTAI {
+		r.DTAI = signedDTAI(r.DTAI)
 	}
 	switch d {
 	case 0:
@@ -233,7 +649,66 @@ func Decode(ip string) (Result, error) {
 		r.Delta = +1
 	}
 
-	return r, nil
+	if opt.baseline != nil && err == nil && r.Delta == 0 && r.DTAI != *opt.baseline {
+		return Result{}, &Error{Code: CodeUnexpectedOffsetChange, Err: fmt.Errorf("dtai %d without leap, baseline %d", r.DTAI, *opt.baseline)}
+	}
+
+	if opt.rejectPast != nil && err == nil && r.Time().Before(*opt.rejectPast) {
+		return Result{}, &Error{Code: CodeStaleAnnouncement, Err: fmt.Errorf("horizon %04d-%02d ended before %s",
+			r.Year, r.Month, opt.rejectPast.UTC().Format(time.RFC3339))}
+	}
+
+	return r, err
+}
+
+// ValidUint32 reports whether u, a network-order 32 bit integer, holds
+// valid leap-second information. It performs the checks of Decode
+// without constructing a Result.
+func ValidUint32(u uint32) bool {
+	return u>>28 == 0xf && crc8(u) == 0x80 &&
+		(u>>15)&3 != 3 && (u>>17)&0x7ff != 0
+}
+
+// CRCVerifier checks and computes the integrity octet of the encoding.
+// Both methods are passed the low 28 bits of the encoded 32 bit word:
+// the 20 bit message of month, d and dTAI fields followed by the 8 bit
+// CRC octet, without the class E nibble. Verify reports whether that
+// word is intact. Compute returns the CRC octet for a word whose CRC
+// octet is zero.
+type CRCVerifier interface {
+	Verify(msg uint32) bool
+	Compute(msg uint32) uint32
+}
+
+// crc8Verifier is the CRCVerifier of the specification.
+type crc8Verifier struct{}
+
+func (crc8Verifier) Verify(msg uint32) bool { return crc8(msg) == 0x80 }
+
+// Compute searches the octet yielding the expected remainder,
+// there is exactly one as the CRC-8 is checked over the message
+// including the CRC octet.
+func (crc8Verifier) Compute(msg uint32) uint32 {
+	for c := uint32(0); c <= 0xff; c++ {
+		if crc8(msg|c) == 0x80 {
+			return c
+		}
+	}
+	panic("unreachable")
+}
+
+// CRC-8 parameters of the encoding.
+const (
+	CRC8Polynomial = 0x12f      // x^8 +x^5 +x^3 +x^2 +x +1
+	CRC8Seed       = 0x54a9abf8 // initial register value
+)
+
+// CRC8 computes the CRC-8 of the encoding over msg, the low 28 bits of
+// the encoded 32 bit word without the class E nibble, MSB first. The
+// word is intact when the result is 0x80, as the CRC octet is part of
+// the message: CRC8(0x41723ff) is 0x80 for 244.23.35.255.
+func CRC8(msg uint32) uint32 {
+	return crc8(msg)
 }
 
 // crc8 computes a MSB first CRC8 with polynomium (x^8 +x^5 +x^3 +x^2 +x +1)
@@ -245,10 +720,10 @@ func Decode(ip string) (Result, error) {
 // PS:  The CRC seed is not random.
 func crc8(u uint32) uint32 {
 	const bits = 28
-	crc := 0x54a9abf8 ^ (u << (32 - bits))
+	crc := CRC8Seed ^ (u << (32 - bits))
 	for i := 0; i < bits; i++ {
 		if crc&(1<<31) != 0 {
-			crc ^= 0x12f << 23
+			crc ^= CRC8Polynomial << 23
 		}
 		crc <<= 1
 	}
@@ -261,11 +736,11 @@ var TestVectors = []struct {
 	Result Result
 	Err    *Error
 }{
-	{"240.3.9.77", Result{1971, 12, 9, +1}, nil},
-	{"240.15.10.108", Result{1972, 6, 10, +1}, nil},
-	{"242.18.28.160", Result{1993, 12, 28, 0}, nil},
-	{"255.76.200.237", Result{2135, 1, 72, -1}, nil},
-	{"127.240.133.76", Result{0, 0, 0, 0}, &Error{Code: -1}},
-	{"255.209.76.40", Result{0, 0, 0, 0}, &Error{Code: -2}},
-	{"241.179.152.73", Result{0, 0, 0, 0}, &Error{Code: -3}},
+	{"240.3.9.77", Result{1971, 12, 9, +1, 2}, nil},
+	{"240.15.10.108", Result{1972, 6, 10, +1, 2}, nil},
+	{"242.18.28.160", Result{1993, 12, 28, 0, 0}, nil},
+	{"255.76.200.237", Result{2135, 1, 72, -1, 1}, nil},
+	{"127.240.133.76", Result{0, 0, 0, 0, 0}, &Error{Code: -1}},
+	{"255.209.76.40", Result{0, 0, 0, 0, 0}, &Error{Code: -2}},
+	{"241.179.152.73", Result{0, 0, 0, 0, 0}, &Error{Code: -3}},
 }
@@ -3,7 +3,11 @@ package dnsleapsecs
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
+	"strings"
 	"testing"
+	"time"
 )
 
 type testResolver struct {
@@ -15,6 +19,9 @@ type testResolver struct {
 var _ Resolver = testResolver{}
 
 func (tr testResolver) LookupHost(ctx context.Context, host string) (addrs []string, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if tr.err != nil {
 		return nil, tr.err
 	}
@@ -24,6 +31,21 @@ func (tr testResolver) LookupHost(ctx context.Context, host string) (addrs []str
 	return tr.addrs, nil
 }
 
+type testResolverTTL struct {
+	testResolver
+	ttl time.Duration
+}
+
+var _ ResolverTTL = testResolverTTL{}
+
+func (tr testResolverTTL) LookupHostTTL(ctx context.Context, host string) (addrs []string, ttl time.Duration, err error) {
+	addrs, err = tr.LookupHost(ctx, host)
+	if err != nil {
+		return nil, 0, err
+	}
+	return addrs, tr.ttl, nil
+}
+
 func TestLookup(t *testing.T) {
 	ctx := context.Background()
 	for _, tv := range TestVectors {
@@ -32,7 +54,7 @@ func TestLookup(t *testing.T) {
 			ip, r, err := Lookup(ctx, tr)
 
 			var e *Error
-			if errors.As(err, &e) && *e != *tv.Err {
+			if errors.As(err, &e) && *e != (Error{Code: tv.Err.Code, IP: tv.IP, Host: "leapsecond.utcd.org"}) {
 				t.Fatalf("got %#v, want: %#v", e, tv.Err)
 			}
 			if ip != tv.IP {
@@ -59,7 +81,7 @@ func TestLookup(t *testing.T) {
 		if want := "240.3.9.77"; ip != want {
 			t.Errorf("got %q, want: %q", ip, want)
 		}
-		if want := (Result{1971, 12, 9, +1}); r != want {
+		if want := (Result{1971, 12, 9, +1, 2}); r != want {
 			t.Errorf("got %#v, want: %#v", r, want)
 		}
 	})
@@ -80,11 +102,11 @@ func TestLookup(t *testing.T) {
 	}
 	t.Run("failedlookup", func(t *testing.T) {
 		tr := testResolver{err: errors.New("some lookup error")}
-		testLookup(t, tr, &Error{Code: -10, Err: tr.err})
+		testLookup(t, tr, &Error{Code: -10, Host: "leapsecond.utcd.org", Err: tr.err})
 	})
 	t.Run("emptyresponse", func(t *testing.T) {
 		tr := testResolver{}
-		testLookup(t, tr, &Error{Code: -11})
+		testLookup(t, tr, &Error{Code: -11, Host: "leapsecond.utcd.org"})
 	})
 	t.Run("manyinvalid", func(t *testing.T) {
 		tr := testResolver{addrs: []string{
@@ -92,7 +114,409 @@ func TestLookup(t *testing.T) {
 			"255.209.76.40",  // invalid checksum
 			"241.179.152.73", // invalid action
 		}}
-		testLookup(t, tr, &Error{Code: -3})
+		testLookup(t, tr, &Error{Code: -3, IP: "241.179.152.73", Host: "leapsecond.utcd.org"})
+	})
+}
+
+func TestLookupConsistent(t *testing.T) {
+	// Every rotation of the test vectors, so each ends up first.
+	ips := make([]string, len(TestVectors))
+	for i, tv := range TestVectors {
+		ips[i] = tv.IP
+	}
+	for i := range ips {
+		addrs := append(append([]string{}, ips[i:]...), ips[:i]...)
+		tr := testResolver{addrs: addrs}
+		ip, r, err := Lookup(context.Background(), tr)
+		dr, derr := Decode(ip)
+		if dr != r {
+			t.Errorf("%v: got %#v, but %q decodes to %#v", addrs, r, ip, dr)
+		}
+		if (err == nil) != (derr == nil) {
+			t.Errorf("%v: got error %v, but %q decodes with error %v", addrs, err, ip, derr)
+		}
+	}
+}
+
+// hostResolver resolves hosts using its map, a host missing from it
+// fails to resolve.
+type hostResolver map[string][]string
+
+func (hr hostResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	addrs, ok := hr[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestDefaultHost(t *testing.T) {
+	ctx := context.Background()
+	hr := hostResolver{
+		"leapsecond.utcd.org":     {"240.3.9.77"},
+		"leapsecond.example.com.": {"244.23.35.255"},
+	}
+	if _, r, err := Lookup(ctx, hr); err != nil || r != (Result{1971, 12, 9, +1, 2}) {
+		t.Errorf("got (%#v, %v)", r, err)
+	}
+
+	defer func(host string) { DefaultHost = host }(DefaultHost)
+	DefaultHost = "leapsecond.example.com."
+	if _, r, err := Lookup(ctx, hr); err != nil || r != (Result{2015, 6, 35, +1, 2}) {
+		t.Errorf("got (%#v, %v)", r, err)
+	}
+	if _, r, _, err := LookupTTL(ctx, hr); err != nil || r != (Result{2015, 6, 35, +1, 2}) {
+		t.Errorf("got (%#v, %v)", r, err)
+	}
+}
+
+// recordingResolver records the hosts looked up.
+type recordingResolver struct {
+	testResolver
+	hosts []string
+}
+
+func (rr *recordingResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	rr.hosts = append(rr.hosts, host)
+	return rr.testResolver.LookupHost(ctx, host)
+}
+
+func TestFetchHost(t *testing.T) {
+	defer func(r Resolver) { defaultResolver = r }(defaultResolver)
+	defer func(host string) { DefaultHost = host }(DefaultHost)
+	ctx := context.Background()
+	rr := &recordingResolver{testResolver: testResolver{addr: "244.23.35.255"}}
+	defaultResolver = rr
+
+	DefaultHost = "leapsecond.mirror.example."
+	Fetch(ctx)
+	Lookup(ctx, rr)
+	FetchHost(ctx, "leapsecond.other.example.")
+	LookupHost(ctx, rr, "leapsecond.utcd.org")
+	want := []string{
+		"leapsecond.mirror.example.",
+		"leapsecond.mirror.example.",
+		"leapsecond.other.example.",
+		"leapsecond.utcd.org",
+	}
+	if strings.Join(rr.hosts, " ") != strings.Join(want, " ") {
+		t.Errorf("got %q, want: %q", rr.hosts, want)
+	}
+}
+
+// deadlineResolver records the deadline of the context of the last
+// lookup.
+type deadlineResolver struct {
+	testResolver
+	deadline time.Time
+}
+
+func (dr *deadlineResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	dr.deadline, _ = ctx.Deadline()
+	return dr.testResolver.LookupHost(ctx, host)
+}
+
+func TestFetchTimeout(t *testing.T) {
+	defer func(r Resolver) { defaultResolver = r }(defaultResolver)
+	defaultResolver = blockingResolver{}
+	ctx := context.Background()
+
+	start := time.Now()
+	_, _, err := FetchTimeout(ctx, 10*time.Millisecond)
+	if !errors.Is(err, ErrLookupFailed) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %#v, want code -10 wrapping %v", err, context.DeadlineExceeded)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("took %v", d)
+	}
+
+	// A sooner deadline of the parent is respected.
+	pctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	if _, _, err := FetchTimeout(pctx, time.Hour); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %#v, want: %v", err, context.DeadlineExceeded)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("took %v", d)
+	}
+	if _, _, err := Fetch(pctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %#v, want: %v", err, context.DeadlineExceeded)
+	}
+
+	// Without deadline Fetch applies the default.
+	dr := &deadlineResolver{testResolver: testResolver{addr: "244.23.35.255"}}
+	defaultResolver = dr
+	start = time.Now()
+	if _, _, err := Fetch(ctx); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if dr.deadline.Before(start.Add(DefaultFetchTimeout)) || dr.deadline.After(time.Now().Add(DefaultFetchTimeout)) {
+		t.Errorf("got deadline in %v, want: %v", dr.deadline.Sub(start), DefaultFetchTimeout)
+	}
+	deadline := time.Now().Add(time.Hour)
+	dctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	Fetch(dctx)
+	if !dr.deadline.Equal(deadline) {
+		t.Errorf("got deadline %v, want: %v", dr.deadline, deadline)
+	}
+}
+
+func TestLookupSeq(t *testing.T) {
+	ctx := context.Background()
+	hr := hostResolver{
+		"bad.example":  {"255.209.76.40"},
+		"good.example": {"240.3.9.77"},
+		"next.example": {"244.23.35.255"},
+	}
+
+	ip, r, err := LookupSeq(ctx, hr, "missing.example", "bad.example", "good.example", "next.example")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if ip != "240.3.9.77" || r != (Result{1971, 12, 9, +1, 2}) {
+		t.Errorf("got (%q, %#v)", ip, r)
+	}
+
+	_, _, err = LookupSeq(ctx, hr, "missing.example", "bad.example")
+	if err == nil {
+		t.Fatal("got no error")
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -10 {
+		t.Errorf("got %#v, want code -10 first", err)
+	}
+	if !strings.Contains(err.Error(), "bad.example: invalid checksum") {
+		t.Errorf("got %q, want bad.example checksum error", err)
+	}
+	if !strings.Contains(err.Error(), "missing.example: lookup failed") {
+		t.Errorf("got %q, want missing.example lookup error", err)
+	}
+}
+
+// blockingResolver blocks until the context is done.
+type blockingResolver struct{}
+
+func (blockingResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// mirrorResolver resolves hosts in its map using hostResolver and blocks
+// the others until the context is done, then signals canceled.
+type mirrorResolver struct {
+	hosts    hostResolver
+	canceled chan string
+}
+
+func (mr mirrorResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if _, ok := mr.hosts[host]; !ok {
+		defer func() { mr.canceled <- host }()
+		return blockingResolver{}.LookupHost(ctx, host)
+	}
+	return mr.hosts.LookupHost(ctx, host)
+}
+
+func TestLookupHosts(t *testing.T) {
+	ctx := context.Background()
+	hr := hostResolver{
+		"bad.example":  {"255.209.76.40"},
+		"good.example": {"240.3.9.77"},
+	}
+
+	ip, r, err := LookupHosts(ctx, hr, []string{"missing.example", "bad.example", "good.example"})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if ip != "240.3.9.77" || r != (Result{1971, 12, 9, +1, 2}) {
+		t.Errorf("got (%q, %#v)", ip, r)
+	}
+
+	_, _, err = LookupHosts(ctx, hr, []string{"missing.example", "bad.example"})
+	if !errors.Is(err, ErrLookupFailed) || !errors.Is(err, ErrInvalidChecksum) {
+		t.Errorf("got %#v, want codes -10 and -2", err)
+	}
+	if want := "missing.example: lookup failed"; !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("got %q, want prefix %q", err, want)
+	}
+	if !strings.Contains(err.Error(), "bad.example: invalid checksum") {
+		t.Errorf("got %q, want bad.example checksum error", err)
+	}
+
+	// A slow mirror doesn't hold up the first valid result and is canceled.
+	mr := mirrorResolver{
+		hosts:    hostResolver{"good.example": {"240.3.9.77"}},
+		canceled: make(chan string, 1),
+	}
+	ip, _, err = LookupHosts(ctx, mr, []string{"slow.example", "good.example"})
+	if err != nil || ip != "240.3.9.77" {
+		t.Errorf("got (%q, %v)", ip, err)
+	}
+	select {
+	case host := <-mr.canceled:
+		if host != "slow.example" {
+			t.Errorf("got %q canceled, want: slow.example", host)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("slow.example not canceled")
+	}
+}
+
+func TestLookupHostChecked(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		addrs    []string
+		disagree bool
+	}{
+		{[]string{"240.3.9.77"}, false},
+		{[]string{"240.3.9.77", "240.3.9.77"}, false},
+		{[]string{"240.3.9.77", "255.209.76.40", "241.179.152.73"}, false},
+		{[]string{"240.3.9.77", "242.18.28.160"}, true},
+		{[]string{"127.240.133.76", "240.3.9.77", "242.18.28.160"}, true},
+	}
+	for _, tt := range tests {
+		tr := testResolver{addrs: tt.addrs}
+		ip, r, disagree, err := LookupHostChecked(ctx, tr, "leapsecond.utcd.org")
+		if err != nil {
+			t.Fatalf("%q: got error: %v", tt.addrs, err)
+		}
+		if ip != "240.3.9.77" || r != (Result{1971, 12, 9, +1, 2}) {
+			t.Errorf("%q: got (%q, %#v)", tt.addrs, ip, r)
+		}
+		if disagree != tt.disagree {
+			t.Errorf("%q: got %t, want: %t", tt.addrs, disagree, tt.disagree)
+		}
+	}
+
+	tr := testResolver{err: errors.New("some lookup error")}
+	_, _, disagree, err := LookupHostChecked(ctx, tr, "leapsecond.utcd.org")
+	var e *Error
+	if disagree || !errors.As(err, &e) || e.Code != -10 || e.Host != "leapsecond.utcd.org" {
+		t.Errorf("got (%t, %#v), want code -10 for host", disagree, err)
+	}
+
+	// Errors carry the host and address like with LookupHost.
+	tr = testResolver{addrs: []string{"255.209.76.40"}}
+	_, _, _, err = LookupHostChecked(ctx, tr, "leapsecond.utcd.org")
+	_, _, want := LookupHost(ctx, tr, "leapsecond.utcd.org")
+	if !errors.As(err, &e) || *e != *want.(*Error) {
+		t.Errorf("got %#v, want: %#v", err, want)
+	}
+	if e.Host != "leapsecond.utcd.org" || e.IP != "255.209.76.40" {
+		t.Errorf("got %#v, want IP and host set", e)
+	}
+}
+
+func TestLookupAll(t *testing.T) {
+	ctx := context.Background()
+	tr := testResolver{addrs: []string{
+		"255.209.76.40", // invalid checksum
+		"240.3.9.77",
+		"240.3.9.77",
+	}}
+	addrs, err := LookupAll(ctx, tr, "leapsecond.utcd.org")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if len(addrs) != 2 {
+		t.Fatalf("got %d addresses, want: 2", len(addrs))
+	}
+	var e *Error
+	if a := addrs[0]; a.IP != "255.209.76.40" || a.Result != (Result{}) || !errors.As(a.Err, &e) || e.Code != -2 {
+		t.Errorf("got %#v, want code -2", a)
+	}
+	if a := addrs[1]; a.IP != "240.3.9.77" || a.Result != (Result{1971, 12, 9, +1, 2}) || a.Err != nil {
+		t.Errorf("got %#v", a)
+	}
+
+	for _, tt := range []struct {
+		tr   testResolver
+		code int
+	}{
+		{testResolver{}, -11},
+		{testResolver{err: errors.New("some lookup error")}, -10},
+	} {
+		addrs, err := LookupAll(ctx, tt.tr, "leapsecond.utcd.org")
+		if !errors.As(err, &e) || e.Code != tt.code {
+			t.Errorf("got %#v, want code %d", err, tt.code)
+		}
+		if addrs != nil {
+			t.Errorf("got %#v", addrs)
+		}
+	}
+}
+
+func TestDedup(t *testing.T) {
+	in := []string{"255.209.76.40", "240.3.9.77", "255.209.76.40", "240.3.9.77", "242.18.28.160"}
+	want := []string{"255.209.76.40", "240.3.9.77", "242.18.28.160"}
+	got := dedup(in)
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want: %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %q, want: %q", got, want)
+		}
+	}
+
+	tr := testResolver{addrs: in}
+	ip, r, err := Lookup(context.Background(), tr)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if ip != "240.3.9.77" || r != (Result{1971, 12, 9, +1, 2}) {
+		t.Errorf("got (%q, %#v)", ip, r)
+	}
+}
+
+func TestLookupTTL(t *testing.T) {
+	ctx := context.Background()
+	want := Result{1971, 12, 9, +1, 2}
+
+	tr := testResolverTTL{testResolver{addr: "240.3.9.77"}, time.Hour}
+	ip, r, ttl, err := LookupTTL(ctx, tr)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if ip != "240.3.9.77" || r != want || ttl != time.Hour {
+		t.Errorf("got (%q, %#v, %v), want: (%q, %#v, %v)", ip, r, ttl, "240.3.9.77", want, time.Hour)
+	}
+
+	t.Run("noresolverttl", func(t *testing.T) {
+		_, r, ttl, err := LookupTTL(ctx, tr.testResolver)
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if r != want || ttl != 0 {
+			t.Errorf("got (%#v, %v), want: (%#v, 0)", r, ttl, want)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tr := testResolverTTL{testResolver{addr: "255.209.76.40"}, time.Hour}
+		_, _, ttl, err := LookupTTL(ctx, tr)
+		if err == nil {
+			t.Fatal("got no error")
+		}
+		if ttl != 0 {
+			t.Errorf("got %v, want: 0", ttl)
+		}
+	})
+
+	t.Run("fetch", func(t *testing.T) {
+		defer func(r Resolver) { defaultResolver = r }(defaultResolver)
+		defaultResolver = tr
+		_, r, ttl, err := FetchTTL(ctx)
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if r != want || ttl != time.Hour {
+			t.Errorf("got (%#v, %v), want: (%#v, %v)", r, ttl, want, time.Hour)
+		}
 	})
 }
 
@@ -101,7 +525,7 @@ func TestDecode(t *testing.T) {
 		t.Run(tv.IP, func(t *testing.T) {
 			r, err := Decode(tv.IP)
 			var e *Error
-			if errors.As(err, &e) && *e != *tv.Err {
+			if errors.As(err, &e) && *e != (Error{Code: tv.Err.Code, IP: tv.IP}) {
 				t.Errorf("got %#v, want: %#v", err, tv.Err)
 			}
 			if r.Year != tv.Result.Year {
@@ -120,6 +544,285 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func FuzzDecode(f *testing.F) {
+	for _, tv := range TestVectors {
+		f.Add(tv.IP)
+	}
+	for _, ip := range []string{
+		"", ".", "...", "notanip", "240.3.9", "240.3.9.77.1", "240.03.9.77",
+		"256.0.0.0", "-1.0.0.0", "240.3.9.77\x00", " 244.23.35.255\n", "::ffff:f417:23ff",
+	} {
+		f.Add(ip)
+	}
+	f.Fuzz(func(t *testing.T, ip string) {
+		r, err := Decode(ip)
+		if err != nil {
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("%q: got %T error: %v", ip, err, err)
+			}
+			if r != (Result{}) {
+				t.Errorf("%q: got result %#v with error: %v", ip, r, err)
+			}
+			return
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("%q: got invalid result %#v: %v", ip, r, err)
+		}
+	})
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	tests := []struct {
+		ip   string
+		code int
+	}{
+		{"127.240.133.76", -1},
+		{"notanip", -6},
+		{"foo.bar", -6},
+		{"240.3.9", -6},
+	}
+	for _, tt := range tests {
+		_, err := Decode(tt.ip)
+		var e *Error
+		if !errors.As(err, &e) || e.Code != tt.code {
+			t.Errorf("%q: got %#v, want code %d", tt.ip, err, tt.code)
+		}
+	}
+}
+
+func TestDecodeRaw(t *testing.T) {
+	r, u, err := DecodeRaw("240.3.9.77")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if r != (Result{1971, 12, 9, +1, 2}) || u != 0xf003094d {
+		t.Errorf("got (%#v, 0x%08x), want: 0xf003094d", r, u)
+	}
+
+	for _, tt := range []struct {
+		ip   string
+		want uint32
+		code int
+	}{
+		{"255.209.76.40", 0xffd14c28, CodeInvalidChecksum},
+		{"241.179.152.73", 0xf1b39849, CodeInvalidAction},
+		{"127.240.133.76", 0x7ff0854c, CodeInvalidAddress},
+	} {
+		_, u, err := DecodeRaw(tt.ip)
+		var e *Error
+		if !errors.As(err, &e) || e.Code != tt.code {
+			t.Errorf("%s: got %#v, want code %d", tt.ip, err, tt.code)
+		}
+		if u != tt.want {
+			t.Errorf("%s: got 0x%08x, want: 0x%08x", tt.ip, u, tt.want)
+		}
+	}
+
+	if _, u, err := DecodeRaw("256.0.0.0"); !errors.Is(err, ErrMalformedAddress) || u != 0 {
+		t.Errorf("got (0x%08x, %v), want code -6", u, err)
+	}
+}
+
+func TestDecodeFull(t *testing.T) {
+	r, b, u, err := DecodeFull("240.3.9.77")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if r != (Result{1971, 12, 9, +1, 2}) {
+		t.Errorf("got %#v", r)
+	}
+	if b != [4]byte{240, 3, 9, 77} {
+		t.Errorf("got %v, want: [240 3 9 77]", b)
+	}
+	if u != 0xf003094d {
+		t.Errorf("got 0x%08x, want: 0xf003094d", u)
+	}
+
+	for _, tv := range TestVectors {
+		if tv.Err == nil {
+			continue
+		}
+		r, b, u, err := DecodeFull(tv.IP)
+		var e *Error
+		if !errors.As(err, &e) || e.Code != tv.Err.Code {
+			t.Errorf("%s: got %#v, want code %d", tv.IP, err, tv.Err.Code)
+		}
+		if r != (Result{}) || b != [4]byte{} || u != 0 {
+			t.Errorf("%s: got (%#v, %v, 0x%08x)", tv.IP, r, b, u)
+		}
+	}
+}
+
+func TestErrorContext(t *testing.T) {
+	_, err := Decode("255.209.76.40")
+	if want := "invalid checksum (255.209.76.40)"; err == nil || err.Error() != want {
+		t.Errorf("got %v, want: %q", err, want)
+	}
+
+	ctx := context.Background()
+	hr := hostResolver{"bad.example": {"255.209.76.40"}}
+	_, _, err = LookupHost(ctx, hr, "bad.example")
+	var e *Error
+	if !errors.As(err, &e) || e.IP != "255.209.76.40" || e.Host != "bad.example" {
+		t.Errorf("got %#v, want IP and host set", err)
+	}
+	_, _, err = LookupHost(ctx, hr, "missing.example")
+	if !errors.As(err, &e) || e.IP != "" || e.Host != "missing.example" || e.Err == nil {
+		t.Errorf("got %#v, want host set wrapping lookup error", err)
+	}
+
+	// Shared errors aren't annotated in place.
+	_, _, err = Lookup(ctx, testResolver{}, NoAnnouncement())
+	if err != ErrNoAnnouncement {
+		t.Errorf("got %#v, want: %#v", err, ErrNoAnnouncement)
+	}
+	if err := annotate(ErrInvalidChecksum, "255.209.76.40", ""); !errors.Is(err, ErrInvalidChecksum) || ErrInvalidChecksum.IP != "" {
+		t.Errorf("got %#v, sentinel %#v", err, ErrInvalidChecksum)
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		err    error
+		target error
+	}{
+		{func() error { _, err := Decode("127.240.133.76"); return err }(), ErrInvalidAddress},
+		{func() error { _, err := Decode("255.209.76.40"); return err }(), ErrInvalidChecksum},
+		{func() error { _, err := Decode("241.179.152.73"); return err }(), ErrInvalidAction},
+		{func() error { _, err := decodeUint32(appendCRC8(0xf<<20|10), &options{}); return err }(), ErrInvalidMonth},
+		{func() error { _, err := Decode("242.18.28.160", Baseline(27)); return err }(), ErrUnexpectedOffsetChange},
+		{func() error { _, err := Decode("notanip"); return err }(), ErrMalformedAddress},
+		{func() error { _, _, err := Lookup(ctx, testResolver{err: errors.New("x")}); return err }(), ErrLookupFailed},
+		{func() error { _, _, err := Lookup(ctx, testResolver{}); return err }(), ErrEmptyResponse},
+		{func() error {
+			_, _, err := Lookup(ctx, testResolverTTL{testResolver{addr: "240.3.9.77"}, 0}, TTLBounds(time.Second, 0))
+			return err
+		}(), ErrTTLOutOfBounds},
+		{func() error { _, err := Encode(Result{}); return err }(), ErrInvalidResult},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.target) {
+			t.Errorf("got %v, want: %v", tt.err, tt.target)
+		}
+		if errors.Is(tt.err, ErrNoAnnouncement) {
+			t.Errorf("%v matches %v", tt.err, ErrNoAnnouncement)
+		}
+		if tt.target != ErrInvalidAddress && errors.Is(tt.err, ErrInvalidAddress) {
+			t.Errorf("%v matches %v", tt.err, ErrInvalidAddress)
+		}
+	}
+
+	// Wrapped errors match as well.
+	err := fmt.Errorf("result 1: %w", &Error{Code: CodeInvalidResult, Err: errors.New("x")})
+	if !errors.Is(err, ErrInvalidResult) {
+		t.Errorf("got %v, want: %v", err, ErrInvalidResult)
+	}
+}
+
+func TestErrorDNSError(t *testing.T) {
+	dnsErr := &net.DNSError{Err: "timeout", Name: "leapsecond.utcd.org", IsTimeout: true}
+	tr := testResolver{err: fmt.Errorf("wrapped: %w", dnsErr)}
+	_, _, err := Lookup(context.Background(), tr)
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("got %T error but wants *Error", err)
+	}
+	de, ok := e.DNSError()
+	if !ok || de != dnsErr {
+		t.Errorf("got (%v, %t), want: (%v, true)", de, ok, dnsErr)
+	}
+	if !de.Timeout() {
+		t.Error("got no timeout")
+	}
+
+	if de, ok := (&Error{Code: -2}).DNSError(); ok || de != nil {
+		t.Errorf("got (%v, %t), want: (nil, false)", de, ok)
+	}
+}
+
+func TestDecodeEpoch(t *testing.T) {
+	// month field 0 and 1, d 0, dtai 10
+	for _, tt := range []struct {
+		u    uint32
+		want Result
+		code int
+	}{
+		{appendCRC8(0xf<<20 | 0<<9 | 10), Result{}, -4},
+		{appendCRC8(0xf<<20 | 1<<9 | 10), Result{1971, 12, 10, 0, 0}, 0},
+	} {
+		ip := fmt.Sprintf("%d.%d.%d.%d", tt.u>>24, (tt.u>>16)&0xff, (tt.u>>8)&0xff, tt.u&0xff)
+		r, err := Decode(ip)
+		var e *Error
+		if tt.code != 0 && (!errors.As(err, &e) || e.Code != tt.code) {
+			t.Errorf("%s: got %#v, want code %d", ip, err, tt.code)
+		}
+		if tt.code == 0 && err != nil {
+			t.Errorf("%s: got error: %v", ip, err)
+		}
+		if r != tt.want {
+			t.Errorf("%s: got %#v, want: %#v", ip, r, tt.want)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	for _, tv := range TestVectors {
+		err := Verify(tv.IP, tv.Result)
+		if tv.Err == nil && err != nil {
+			t.Errorf("%s: got error: %v", tv.IP, err)
+		}
+		var e *Error
+		if tv.Err != nil && (!errors.As(err, &e) || *e != (Error{Code: tv.Err.Code, IP: tv.IP})) {
+			t.Errorf("%s: got %#v, want: %#v", tv.IP, err, tv.Err)
+		}
+	}
+
+	tests := []struct {
+		want Result
+		err  string
+	}{
+		{Result{1972, 12, 9, +1, 2}, "240.3.9.77: year is 1971, want 1972"},
+		{Result{1971, 6, 9, +1, 2}, "240.3.9.77: month is 12, want 6"},
+		{Result{1971, 12, 10, +1, 2}, "240.3.9.77: dtai is 9, want 10"},
+		{Result{1971, 12, 9, 0, 0}, "240.3.9.77: delta is 1, want 0"},
+	}
+	for _, tt := range tests {
+		err := Verify("240.3.9.77", tt.want)
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("got %v, want: %s", err, tt.err)
+		}
+	}
+}
+
+func TestValidUint32(t *testing.T) {
+	for _, tv := range TestVectors {
+		u, _ := parseIPv4(tv.IP)
+		if got, want := ValidUint32(u), tv.Err == nil; got != want {
+			t.Errorf("%s: got %t, want: %t", tv.IP, got, want)
+		}
+	}
+	if ValidUint32(appendCRC8(0xf<<20 | 10)) {
+		t.Error("month field 0 is valid")
+	}
+}
+
+func BenchmarkValidUint32(b *testing.B) {
+	u := uint32(0xf41723ff)
+	for i := 0; i < b.N; i++ {
+		ValidUint32(u)
+	}
+}
+
+func BenchmarkDecodeUint32(b *testing.B) {
+	u := uint32(0xf41723ff)
+	opt := newOptions(nil)
+	for i := 0; i < b.N; i++ {
+		decodeUint32(u, opt)
+	}
+}
+
 func TestCRC8(t *testing.T) {
 	const in = uint32(0x41723ff)
 	const want = 0x80
@@ -128,3 +831,42 @@ func TestCRC8(t *testing.T) {
 		t.Errorf("got 0x%x, want: 0x%x", got, want)
 	}
 }
+
+func TestCRC8Exported(t *testing.T) {
+	if got := CRC8(0x41723ff); got != 0x80 {
+		t.Errorf("got 0x%x, want: 0x80", got)
+	}
+	for _, tv := range TestVectors {
+		u, err := parseIPv4(tv.IP)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if valid := CRC8(u&(1<<28-1)) == 0x80; valid != (tv.Err == nil || tv.Err.Code == -3) {
+			t.Errorf("%s: got valid %t", tv.IP, valid)
+		}
+	}
+}
+
+func FuzzCRC8(f *testing.F) {
+	for _, tv := range TestVectors {
+		if u, err := parseIPv4(tv.IP); err == nil {
+			f.Add(u >> 8)
+		}
+	}
+	f.Fuzz(func(t *testing.T, msg uint32) {
+		if crc := crc8(msg); crc > 0xff {
+			t.Fatalf("crc8(0x%x) = 0x%x out of range", msg, crc)
+		}
+
+		// Every single-bit error in a valid word must be detected.
+		u := appendCRC8(msg & 0xfffff)
+		if crc8(u) != 0x80 {
+			t.Fatalf("crc8(0x%x) = 0x%x, want: 0x80", u, crc8(u))
+		}
+		for i := 0; i < 28; i++ {
+			if v := u ^ 1<<i; crc8(v) == 0x80 {
+				t.Errorf("flipping bit %d of 0x%x not detected", i, u)
+			}
+		}
+	})
+}
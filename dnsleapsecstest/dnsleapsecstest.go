@@ -0,0 +1,175 @@
+// Package dnsleapsecstest provides utilities for testing resolvers
+// used with package dnsleapsecs.
+package dnsleapsecstest
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dwlnetnl/dnsleapsecs"
+)
+
+// Timeout is how long AssertRespectsContext waits for a resolver.
+var Timeout = time.Second
+
+// AssertRespectsContext verifies r returns an error promptly, within
+// Timeout, when its context is already cancelled. If r implements
+// dnsleapsecs.ResolverTTL, LookupHostTTL is verified as well.
+func AssertRespectsContext(t testing.TB, r dnsleapsecs.Resolver) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	check := func(name string, lookup func() error) {
+		t.Helper()
+		done := make(chan error, 1)
+		go func() { done <- lookup() }()
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Errorf("%s with cancelled context: got no error", name)
+			}
+		case <-time.After(Timeout):
+			t.Errorf("%s with cancelled context: no return within %v", name, Timeout)
+		}
+	}
+	check("LookupHost", func() error {
+		_, err := r.LookupHost(ctx, "leapsecond.utcd.org")
+		return err
+	})
+	if rt, ok := r.(dnsleapsecs.ResolverTTL); ok {
+		check("LookupHostTTL", func() error {
+			_, _, err := rt.LookupHostTTL(ctx, "leapsecond.utcd.org")
+			return err
+		})
+	}
+}
+
+// StaticResolver is a dnsleapsecs.ResolverTTL returning the same
+// addresses for every host.
+type StaticResolver struct {
+	Addrs []string
+	TTL   time.Duration
+	Err   error // returned instead of Addrs when set
+}
+
+// LookupHost implements dnsleapsecs.Resolver.
+func (sr StaticResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	addrs, _, err := sr.LookupHostTTL(ctx, host)
+	return addrs, err
+}
+
+// LookupHostTTL implements dnsleapsecs.ResolverTTL.
+func (sr StaticResolver) LookupHostTTL(ctx context.Context, host string) ([]string, time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	if sr.Err != nil {
+		return nil, 0, sr.Err
+	}
+	return sr.Addrs, sr.TTL, nil
+}
+
+// Server is a DNS server on the loopback interface answering every A
+// query with the same addresses, other queries with no records.
+type Server struct {
+	Addr string // "host:port" the server listens on
+
+	conn  net.PacketConn
+	addrs []net.IP
+	ttl   uint32
+	done  chan struct{}
+}
+
+// NewServer starts a Server answering with addrs and ttl.
+// The caller should call Close when finished, to shut it down.
+func NewServer(addrs []string, ttl time.Duration) *Server {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		panic("dnsleapsecstest: failed to listen: " + err.Error())
+	}
+	s := &Server{
+		Addr: conn.LocalAddr().String(),
+		conn: conn,
+		ttl:  uint32(ttl / time.Second),
+		done: make(chan struct{}),
+	}
+	for _, a := range addrs {
+		s.addrs = append(s.addrs, net.ParseIP(a).To4())
+	}
+	go s.serve()
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.conn.Close()
+	<-s.done
+}
+
+func (s *Server) serve() {
+	defer close(s.done)
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if resp := Answer(buf[:n], s.addrs, s.ttl); resp != nil {
+			s.conn.WriteTo(resp, addr)
+		}
+	}
+}
+
+// Answer returns the DNS wire format response to query, answering an
+// A question with addrs and ttl (in seconds) and other questions with
+// no records. Entries of addrs that aren't IPv4 addresses are skipped,
+// as are those beyond the 65535 records a response can hold. It returns
+// nil if query is malformed.
+func Answer(query []byte, addrs []net.IP, ttl uint32) []byte {
+	if len(query) < 12 || query[4] != 0 || query[5] != 1 {
+		return nil
+	}
+	// Skip the question name.
+	i := 12
+	for i < len(query) && query[i] != 0 {
+		i += 1 + int(query[i])
+	}
+	i += 5
+	if i > len(query) {
+		return nil
+	}
+	question := query[12:i]
+	qtype := uint16(query[i-4])<<8 | uint16(query[i-3])
+
+	var answers [][]byte
+	if qtype == 1 {
+		for _, a := range addrs {
+			a = a.To4()
+			if a == nil || len(answers) == 0xffff {
+				continue
+			}
+			answers = append(answers, []byte{
+				0xc0, 12, // name pointer to question
+				0, 1, // type A
+				0, 1, // class IN
+				byte(ttl >> 24), byte(ttl >> 16), byte(ttl >> 8), byte(ttl),
+				0, 4, a[0], a[1], a[2], a[3],
+			})
+		}
+	}
+	resp := []byte{
+		query[0], query[1], // ID
+		0x80 | query[2]&0x01, 0x80, // QR, RD copied, RA
+		0, 1, // QDCOUNT
+		byte(len(answers) >> 8), byte(len(answers)), // ANCOUNT
+		0, 0, 0, 0, // NSCOUNT, ARCOUNT
+	}
+	resp = append(resp, question...)
+	for _, a := range answers {
+		resp = append(resp, a...)
+	}
+	return resp
+}
@@ -0,0 +1,63 @@
+package dnsleapsecstest
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dwlnetnl/dnsleapsecs"
+)
+
+func TestStaticResolver(t *testing.T) {
+	sr := StaticResolver{Addrs: []string{"244.23.35.255"}, TTL: time.Hour}
+	AssertRespectsContext(t, sr)
+
+	ip, r, ttl, err := dnsleapsecs.LookupTTL(context.Background(), sr)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if ip != "244.23.35.255" || r != (dnsleapsecs.Result{Year: 2015, Month: 6, DTAI: 35, Delta: +1, Action: 2}) || ttl != time.Hour {
+		t.Errorf("got (%q, %#v, %v)", ip, r, ttl)
+	}
+}
+
+func TestDefaultResolver(t *testing.T) {
+	AssertRespectsContext(t, net.DefaultResolver)
+}
+
+func TestAnswer(t *testing.T) {
+	query := []byte{
+		0x12, 0x34, // ID
+		0x01, 0, // RD
+		0, 1, // QDCOUNT
+		0, 0, 0, 0, 0, 0, // ANCOUNT, NSCOUNT, ARCOUNT
+		4, 't', 'e', 's', 't', 0, // test.
+		0, 1, 0, 1, // type A, class IN
+	}
+	ancount := func(resp []byte) int { return int(resp[6])<<8 | int(resp[7]) }
+
+	addrs := []net.IP{nil, net.ParseIP("2001:db8::1"), net.ParseIP("244.23.35.255"), {240, 3, 9, 77}}
+	resp := Answer(query, addrs, 3600)
+	if resp == nil {
+		t.Fatal("got no response")
+	}
+	if n := ancount(resp); n != 2 {
+		t.Errorf("got %d answers, want: 2", n)
+	}
+	if want := len(query) + 2*16; len(resp) != want {
+		t.Errorf("got %d bytes, want: %d", len(resp), want)
+	}
+
+	addrs = nil
+	for i := 0; i < 300; i++ {
+		addrs = append(addrs, net.IPv4(240, 3, 9, byte(i)))
+	}
+	if n := ancount(Answer(query, addrs, 3600)); n != 300 {
+		t.Errorf("got %d answers, want: 300", n)
+	}
+
+	if resp := Answer(query[:11], addrs, 3600); resp != nil {
+		t.Errorf("malformed query: got %x", resp)
+	}
+}
@@ -0,0 +1,187 @@
+package dnsleapsecs
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DecodeDoHJSON selects and decodes leap-second information from a DNS
+// over HTTPS JSON response (application/dns-json) as served by Google
+// and Cloudflare, like LookupHost does. The data of the A records in
+// the Answer array are the candidate addresses.
+//
+// A malformed response or one with a non-zero Status is reported as a
+// failed lookup (-10), one without A records as empty response (-11).
+func DecodeDoHJSON(b []byte, opts ...Option) (string, Result, error) {
+	var resp struct {
+		Status int
+		Answer []struct {
+			Type int    `json:"type"`
+			Data string `json:"data"`
+		}
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return "", Result{}, &Error{Code: CodeLookupFailed, Err: err}
+	}
+	if resp.Status != 0 {
+		return "", Result{}, &Error{Code: CodeLookupFailed, Err: fmt.Errorf("response status %d", resp.Status)}
+	}
+	var ips []string
+	for _, a := range resp.Answer {
+		if a.Type == 1 {
+			ips = append(ips, a.Data)
+		}
+	}
+	return selectAddr(ips, newOptions(opts))
+}
+
+// DefaultDoHURL is the DNS over HTTPS endpoint used by a DoHResolver
+// without URL.
+const DefaultDoHURL = "https://cloudflare-dns.com/dns-query"
+
+// DoHResolver is a ResolverTTL querying A records using DNS over HTTPS
+// (RFC 8484) in DNS wire format, for environments where plain DNS is
+// blocked. HTTP and transport errors are returned as is, lookups wrap
+// them as lookup failed (-10).
+type DoHResolver struct {
+	URL    string       // endpoint, DefaultDoHURL if empty
+	Client *http.Client // http.DefaultClient if nil
+	Post   bool         // use POST instead of GET
+}
+
+// LookupHost implements Resolver.
+func (dr *DoHResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	addrs, _, err := dr.LookupHostTTL(ctx, host)
+	return addrs, err
+}
+
+// LookupHostTTL implements ResolverTTL. The TTL is the lowest of the
+// A records.
+func (dr *DoHResolver) LookupHostTTL(ctx context.Context, host string) ([]string, time.Duration, error) {
+	query, err := dohQuery(host)
+	if err != nil {
+		return nil, 0, err
+	}
+	url := dr.URL
+	if url == "" {
+		url = DefaultDoHURL
+	}
+	var req *http.Request
+	if dr.Post {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(query))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/dns-message")
+		}
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet,
+			url+"?dns="+base64.RawURLEncoding.EncodeToString(query), nil)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/dns-message")
+
+	client := dr.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("doh: %s", resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, 0, err
+	}
+	return dohAnswer(b)
+}
+
+// dohQuery returns the DNS wire format query for the A records of host,
+// with ID 0 as RFC 8484 recommends for caching.
+func dohQuery(host string) ([]byte, error) {
+	q := []byte{
+		0, 0, // ID
+		0x01, 0, // RD
+		0, 1, // QDCOUNT
+		0, 0, 0, 0, 0, 0, // ANCOUNT, NSCOUNT, ARCOUNT
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("doh: invalid host %q", host)
+		}
+		q = append(q, byte(len(label)))
+		q = append(q, label...)
+	}
+	return append(q, 0, 0, 1, 0, 1), nil // root, type A, class IN
+}
+
+// dohAnswer returns the addresses of the A records in the DNS wire
+// format response b and their lowest TTL.
+func dohAnswer(b []byte) ([]string, time.Duration, error) {
+	errMalformed := errors.New("doh: malformed response")
+	if len(b) < 12 {
+		return nil, 0, errMalformed
+	}
+	if rcode := b[3] & 0x0f; rcode != 0 {
+		return nil, 0, fmt.Errorf("doh: response code %d", rcode)
+	}
+	qd := int(b[4])<<8 | int(b[5])
+	an := int(b[6])<<8 | int(b[7])
+
+	// skipName returns the offset after the (compressed) name at i.
+	skipName := func(i int) int {
+		for i < len(b) {
+			switch n := b[i]; {
+			case n == 0:
+				return i + 1
+			case n&0xc0 == 0xc0:
+				return i + 2
+			default:
+				i += 1 + int(n)
+			}
+		}
+		return len(b) + 1
+	}
+	i := 12
+	for ; qd > 0; qd-- {
+		i = skipName(i) + 4
+	}
+	var addrs []string
+	var ttl uint32
+	for ; an > 0; an-- {
+		i = skipName(i)
+		if i+10 > len(b) {
+			return nil, 0, errMalformed
+		}
+		typ := int(b[i])<<8 | int(b[i+1])
+		class := int(b[i+2])<<8 | int(b[i+3])
+		t := binary.BigEndian.Uint32(b[i+4:])
+		n := int(b[i+8])<<8 | int(b[i+9])
+		i += 10
+		if i+n > len(b) {
+			return nil, 0, errMalformed
+		}
+		if typ == 1 && class == 1 && n == 4 {
+			addrs = append(addrs, net.IP(b[i:i+4]).String())
+			if len(addrs) == 1 || t < ttl {
+				ttl = t
+			}
+		}
+		i += n
+	}
+	return addrs, time.Duration(ttl) * time.Second, nil
+}
@@ -0,0 +1,81 @@
+package dnsleapsecs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDecodeDoHJSON(t *testing.T) {
+	const resp = `{
+		"Status": 0, "TC": false, "RD": true, "RA": true, "AD": false, "CD": false,
+		"Question": [{"name": "leapsecond.utcd.org", "type": 1}],
+		"Answer": [
+			{"name": "leapsecond.utcd.org", "type": 5, "TTL": 3600, "data": "alias.utcd.org."},
+			{"name": "alias.utcd.org", "type": 1, "TTL": 3600, "data": "255.209.76.40"},
+			{"name": "alias.utcd.org", "type": 1, "TTL": 3600, "data": "244.23.35.255"}
+		]
+	}`
+	ip, r, err := DecodeDoHJSON([]byte(resp))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if ip != "244.23.35.255" || r != (Result{2015, 6, 35, +1, 2}) {
+		t.Errorf("got (%q, %#v)", ip, r)
+	}
+
+	tests := []struct {
+		resp string
+		code int
+	}{
+		{`{"Status": 0, "Answer": [{"type": 1, "data": "255.209.76.40"}]}`, -2},
+		{`{"Status": 0}`, -11},
+		{`{"Status": 0, "Answer": [{"type": 28, "data": "2001:db8::1"}]}`, -11},
+		{`{"Status": 3}`, -10},
+		{`not json`, -10},
+	}
+	for _, tt := range tests {
+		_, _, err := DecodeDoHJSON([]byte(tt.resp))
+		var e *Error
+		if !errors.As(err, &e) || e.Code != tt.code {
+			t.Errorf("%s: got %#v, want code %d", tt.resp, err, tt.code)
+		}
+	}
+}
+
+func TestDoHAnswer(t *testing.T) {
+	query, err := dohQuery("leapsecond.utcd.org.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Answer with two A records and a CNAME, using name compression.
+	resp := append([]byte{0, 0, 0x81, 0x80, 0, 1, 0, 3, 0, 0, 0, 0}, query[12:]...)
+	resp = append(resp,
+		0xc0, 12, 0, 5, 0, 1, 0, 0, 0, 60, 0, 2, 0xc0, 12,
+		0xc0, 12, 0, 1, 0, 1, 0, 0, 0x0e, 0x10, 0, 4, 244, 23, 35, 255,
+		0xc0, 12, 0, 1, 0, 1, 0, 0, 0x01, 0x2c, 0, 4, 240, 3, 9, 77,
+	)
+	addrs, ttl, err := dohAnswer(resp)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if len(addrs) != 2 || addrs[0] != "244.23.35.255" || addrs[1] != "240.3.9.77" {
+		t.Errorf("got %q", addrs)
+	}
+	if ttl != 5*time.Minute {
+		t.Errorf("got %v, want: %v", ttl, 5*time.Minute)
+	}
+
+	for _, b := range [][]byte{
+		resp[:11],
+		resp[:len(resp)-2],
+		append([]byte{0, 0, 0x81, 0x83}, resp[4:]...), // NXDOMAIN
+	} {
+		if _, _, err := dohAnswer(b); err == nil {
+			t.Errorf("%x: got no error", b)
+		}
+	}
+	if _, err := dohQuery("leapsecond..utcd.org"); err == nil {
+		t.Error("got no error")
+	}
+}
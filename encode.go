@@ -0,0 +1,140 @@
+package dnsleapsecs
+
+import (
+	"fmt"
+	"io"
+)
+
+// Validate reports whether r can be encoded. The announced horizon must
+// fit the 11 bit month field (December 1971 onwards), DTAI the unsigned
+// 7 bit dTAI field and Delta must be -1, 0 or +1. A non-zero Action
+// must be the one of Delta.
+func (r Result) Validate() error {
+	if r.Month < 1 || r.Month > 12 {
+		return &Error{Code: CodeInvalidResult, Err: fmt.Errorf("month %d out of range [1,12]", r.Month)}
+	}
+	if m := MonthsSince1971(r.Year, r.Month); m < 1 || m > 0x7ff {
+		return &Error{Code: CodeInvalidResult, Err: fmt.Errorf("horizon %04d-%02d out of range", r.Year, r.Month)}
+	}
+	if r.DTAI < 0 || r.DTAI > 0x7f {
+		return &Error{Code: CodeInvalidResult, Err: fmt.Errorf("dtai %d out of range [0,127]", r.DTAI)}
+	}
+	if r.Delta < -1 || r.Delta > +1 {
+		return &Error{Code: CodeInvalidResult, Err: fmt.Errorf("delta %d out of range [-1,+1]", r.Delta)}
+	}
+	if r.Action != 0 && r.Action != int(actionCode(r.Delta)) {
+		return &Error{Code: CodeInvalidResult, Err: fmt.Errorf("action %d contradicts delta %d", r.Action, r.Delta)}
+	}
+	return nil
+}
+
+// Encode encodes leap-second information into a numeric IPv4 string
+// ("244.23.35.255"). It is the inverse of Decode.
+func Encode(r Result) (string, error) {
+	u, err := EncodeUint32(r)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d.%d.%d.%d", u>>24, (u>>16)&0xff, (u>>8)&0xff, u&0xff), nil
+}
+
+// Canonical reports whether ip is the canonical encoding of what it
+// decodes into, which is unique for a Result: it decodes ip, encodes the
+// Result again and compares. It's a strictness check for validating
+// zones. The decode error is returned for an address that doesn't
+// decode.
+func Canonical(ip string) (bool, error) {
+	r, err := Decode(ip)
+	if err != nil {
+		return false, err
+	}
+	enc, err := Encode(r)
+	if err != nil {
+		return false, err
+	}
+	return enc == ip, nil
+}
+
+// EncodeTo writes the encoding of each of results on its own line to w,
+// as when producing A records for a zone file. It stops at the first
+// invalid Result, returning an error holding its index. The number of
+// records written is returned.
+func EncodeTo(w io.Writer, results []Result) (int, error) {
+	for i, r := range results {
+		ip, err := Encode(r)
+		if err != nil {
+			return i, fmt.Errorf("result %d: %w", i, err)
+		}
+		if _, err := io.WriteString(w, ip+"\n"); err != nil {
+			return i, err
+		}
+	}
+	return len(results), nil
+}
+
+// EncodeAll encodes each of results, returning a map from each valid
+// Result to its encoding. An invalid Result is left out of the map,
+// instead an error holding its index is returned in the error slice.
+func EncodeAll(results []Result) (map[Result]string, []error) {
+	m := make(map[Result]string, len(results))
+	var errs []error
+	for i, r := range results {
+		ip, err := Encode(r)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("result %d: %w", i, err))
+			continue
+		}
+		m[r] = ip
+	}
+	return m, errs
+}
+
+// EncodeUint32 encodes leap-second information into the 32 bit word
+// that makes up the IPv4 address, including class E nibble and CRC-8.
+func EncodeUint32(r Result) (uint32, error) {
+	if err := r.Validate(); err != nil {
+		return 0, err
+	}
+
+	u := uint32(0xf)
+	u = u<<11 | uint32(MonthsSince1971(r.Year, r.Month))
+	u = u<<2 | actionCode(r.Delta)
+	u = u<<7 | uint32(r.DTAI)
+	return appendCRC8(u), nil
+}
+
+// appendCRC8 shifts u one octet left and fills it with the CRC-8.
+func appendCRC8(u uint32) uint32 {
+	u <<= 8
+	return u | crc8Verifier{}.Compute(u&(1<<28-1))
+}
+
+// MonthsSince1971 returns the value of the month field for the given
+// horizon: the count of months since december 1971, so December 1971
+// is 1 and June 2015 is 523.
+func MonthsSince1971(year, month int) int {
+	return (year-1971)*12 + month - 11
+}
+
+// EncodeMonthCount is like Encode, but takes the horizon as the raw
+// month field value, see MonthsSince1971.
+func EncodeMonthCount(months, dtai, delta int) (string, error) {
+	if months < 1 || months > 0x7ff {
+		return "", &Error{Code: CodeInvalidResult, Err: fmt.Errorf("month count %d out of range [1,2047]", months)}
+	}
+	mn := months + 10
+	return Encode(Result{
+		Year:  1971 + mn/12,
+		Month: 1 + mn%12,
+		DTAI:  dtai,
+		Delta: delta,
+	})
+}
+
+// Packed returns the 32 bit word r encodes into, including class E
+// nibble and CRC-8. It's EncodeUint32 as a method, meant for checking
+// the bit fields by hand against the specification: 240.3.9.77 is
+// 0xf003094d.
+func (r Result) Packed() (uint32, error) {
+	return EncodeUint32(r)
+}
@@ -0,0 +1,229 @@
+package dnsleapsecs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	// Encoding pins TestVectors to real encodings, guarding against
+	// accidental edits.
+	for _, tv := range TestVectors {
+		if tv.Err != nil {
+			if tv.Result != (Result{}) {
+				t.Errorf("%s: got %#v for error entry, want zero Result", tv.IP, tv.Result)
+			}
+			continue
+		}
+		t.Run(tv.IP, func(t *testing.T) {
+			ip, err := Encode(tv.Result)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if ip != tv.IP {
+				t.Errorf("got %q, want: %q", ip, tv.IP)
+			}
+		})
+	}
+
+	t.Run("bulletinc49", func(t *testing.T) {
+		ip, err := Encode(Result{2015, 6, 35, +1, 2})
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if want := "244.23.35.255"; ip != want {
+			t.Errorf("got %q, want: %q", ip, want)
+		}
+	})
+
+	for _, r := range []Result{
+		{1971, 11, 9, 0, 0},
+		{2142, 10, 0, 0, 0},
+		{2015, 0, 35, 0, 0},
+		{2015, 13, 35, 0, 0},
+		{2015, 6, -1, 0, 0},
+		{2015, 6, 128, 0, 0},
+		{2015, 6, 35, 2, 0},
+		{2015, 6, 35, +1, 1},
+		{2015, 6, 35, 0, 2},
+		{2015, 6, 35, 0, 3},
+	} {
+		_, err := Encode(r)
+		var e *Error
+		if !errors.As(err, &e) || e.Code != -20 {
+			t.Errorf("%#v: got %#v, want code -20", r, err)
+		}
+	}
+}
+
+func TestEncodeAction(t *testing.T) {
+	// A zero Action is derived from Delta.
+	ip, err := Encode(Result{Year: 2015, Month: 6, DTAI: 35, Delta: +1})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if want := "244.23.35.255"; ip != want {
+		t.Errorf("got %q, want: %q", ip, want)
+	}
+
+	for _, tv := range TestVectors {
+		if tv.Err != nil {
+			continue
+		}
+		if want := int(actionCode(tv.Result.Delta)); tv.Result.Action != want {
+			t.Errorf("%s: got action %d, want: %d", tv.IP, tv.Result.Action, want)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	for _, tv := range TestVectors {
+		ok, err := Canonical(tv.IP)
+		if tv.Err != nil {
+			var e *Error
+			if ok || !errors.As(err, &e) || e.Code != tv.Err.Code {
+				t.Errorf("%s: got (%t, %#v), want code %d", tv.IP, ok, err, tv.Err.Code)
+			}
+			continue
+		}
+		if !ok || err != nil {
+			t.Errorf("%s: got (%t, %v), want: (true, <nil>)", tv.IP, ok, err)
+		}
+	}
+
+	// Leading zeros don't parse, so they can't be a variant.
+	if _, err := Canonical("240.3.9.077"); err == nil {
+		t.Error("got no error")
+	}
+}
+
+func TestEncodeUint32(t *testing.T) {
+	u, err := EncodeUint32(Result{1971, 12, 9, +1, 2})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if want := uint32(0xf003094d); u != want {
+		t.Errorf("got 0x%x, want: 0x%x", u, want)
+	}
+}
+
+func TestEncodeTo(t *testing.T) {
+	results := []Result{
+		{1971, 12, 9, +1, 2},
+		{2015, 6, 35, +1, 2},
+		{2015, 6, 35, 2, 0},
+		{1993, 12, 28, 0, 0},
+	}
+	var b strings.Builder
+	n, err := EncodeTo(&b, results)
+	if n != 2 {
+		t.Errorf("got %d records, want: 2", n)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -20 {
+		t.Errorf("got %#v, want code -20", err)
+	}
+	if err == nil || !strings.HasPrefix(err.Error(), "result 2: ") {
+		t.Errorf("got %v, want index 2", err)
+	}
+	if want := "240.3.9.77\n244.23.35.255\n"; b.String() != want {
+		t.Errorf("got %q, want: %q", b.String(), want)
+	}
+
+	b.Reset()
+	n, err = EncodeTo(&b, append(results[:2:2], results[3]))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("got %d records, want: 3", n)
+	}
+	if want := "240.3.9.77\n244.23.35.255\n242.18.28.160\n"; b.String() != want {
+		t.Errorf("got %q, want: %q", b.String(), want)
+	}
+}
+
+func TestResultPacked(t *testing.T) {
+	for _, tv := range TestVectors {
+		if tv.Err != nil {
+			continue
+		}
+		u, err := tv.Result.Packed()
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		want, _ := parseIPv4(tv.IP)
+		if u != want {
+			t.Errorf("%s: got 0x%08x, want: 0x%08x", tv.IP, u, want)
+		}
+	}
+}
+
+func TestEncodeMonthCount(t *testing.T) {
+	for _, tv := range TestVectors {
+		if tv.Err != nil {
+			continue
+		}
+		r := tv.Result
+		ip, err := EncodeMonthCount(MonthsSince1971(r.Year, r.Month), r.DTAI, r.Delta)
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if ip != tv.IP {
+			t.Errorf("got %q, want: %q", ip, tv.IP)
+		}
+	}
+
+	if got := MonthsSince1971(2015, 6); got != 523 {
+		t.Errorf("got %d, want: 523", got)
+	}
+
+	for _, months := range []int{0, -1, 0x800} {
+		_, err := EncodeMonthCount(months, 35, 0)
+		var e *Error
+		if !errors.As(err, &e) || e.Code != -20 {
+			t.Errorf("%d: got %#v, want code -20", months, err)
+		}
+	}
+	if ip, err := EncodeMonthCount(0x7ff, 0, 0); err != nil {
+		t.Errorf("got error: %v", err)
+	} else if r, _ := Decode(ip); MonthsSince1971(r.Year, r.Month) != 0x7ff {
+		t.Errorf("got %#v", r)
+	}
+}
+
+func TestEncodeAll(t *testing.T) {
+	results := []Result{
+		{1971, 12, 9, +1, 2},
+		{2015, 6, 35, 2, 0},
+		{2015, 6, 35, +1, 2},
+		{2015, 6, 128, 0, 0},
+		{1971, 12, 9, +1, 2},
+	}
+	m, errs := EncodeAll(results)
+	want := map[Result]string{
+		{1971, 12, 9, +1, 2}: "240.3.9.77",
+		{2015, 6, 35, +1, 2}: "244.23.35.255",
+	}
+	if len(m) != len(want) {
+		t.Errorf("got %v, want: %v", m, want)
+	}
+	for r, ip := range want {
+		if m[r] != ip {
+			t.Errorf("%#v: got %q, want: %q", r, m[r], ip)
+		}
+	}
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want: 2", len(errs))
+	}
+	for i, prefix := range []string{"result 1: ", "result 3: "} {
+		var e *Error
+		if !errors.As(errs[i], &e) || e.Code != -20 {
+			t.Errorf("got %#v, want code -20", errs[i])
+		}
+		if !strings.HasPrefix(errs[i].Error(), prefix) {
+			t.Errorf("got %q, want prefix %q", errs[i], prefix)
+		}
+	}
+}
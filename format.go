@@ -0,0 +1,134 @@
+package dnsleapsecs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeMonth returns the announced month as a time.Month. Month is
+// 1-based like time.Month, so January is 1.
+func (r Result) TimeMonth() time.Month {
+	return time.Month(r.Month)
+}
+
+// MonthName returns the English name of the announced month ("June").
+func (r Result) MonthName() string {
+	return r.TimeMonth().String()
+}
+
+// HumanHorizon returns the announced horizon in English ("June 2015").
+func (r Result) HumanHorizon() string {
+	return r.MonthName() + " " + strconv.Itoa(r.Year)
+}
+
+// StructuredData returns r as key=value pairs for structured logging,
+// such as RFC 5424 syslog: "dtai=35 delta=1 direction=positive
+// horizon=2015-06". The keys dtai, delta, direction and horizon are
+// stable, direction is one of the LeapDirection strings.
+func (r Result) StructuredData() string {
+	return fmt.Sprintf("dtai=%d delta=%d direction=%s horizon=%04d-%02d",
+		r.DTAI, r.Delta, r.Direction(), r.Year, r.Month)
+}
+
+// String returns r in a human-readable form for logging, for example
+// "2015-06 dTAI=35 (UTC=TAI-35s), +1 at month end". When Delta is zero
+// the pending change reads "no change".
+func (r Result) String() string {
+	change := "no change"
+	if r.Delta != 0 {
+		change = fmt.Sprintf("%+d at month end", r.Delta)
+	}
+	return fmt.Sprintf("%04d-%02d dTAI=%d (UTC=TAI%+ds), %s",
+		r.Year, r.Month, r.DTAI, -r.DTAI, change)
+}
+
+// String returns a in a human-readable form for logging: the one of the
+// Result followed by the derived offset and, if a leap-second is
+// announced, whether it is pending, for example "2015-06 dTAI=35
+// (UTC=TAI-35s), +1 at month end; offset 35, leap pending at
+// 2015-07-01T00:00:00Z". It overrides the String of the embedded Result,
+// which would leave out the derived values.
+func (a Announcement) String() string {
+	s := fmt.Sprintf("%v; offset %d", a.Result, a.Offset)
+	switch {
+	case a.Leap.IsZero():
+	case a.Pending:
+		s += ", leap pending at " + a.Leap.Format(time.RFC3339)
+	default:
+		s += ", leap passed at " + a.Leap.Format(time.RFC3339)
+	}
+	return s
+}
+
+// OffsetISO returns the offset of UTC relative to TAI during the
+// announced month as an ISO 8601 duration, which is minus DTAI as in
+// UTC - TAI = -dTAI: a DTAI of 35 gives "-PT35S", zero gives "PT0S".
+func (r Result) OffsetISO() string {
+	return isoSeconds(-r.DTAI)
+}
+
+// OffsetAfterHorizonISO is like OffsetISO, but for the offset after the
+// announced month, see OffsetAfterHorizon.
+func (r Result) OffsetAfterHorizonISO() string {
+	return isoSeconds(-r.OffsetAfterHorizon())
+}
+
+// isoSeconds formats n seconds as an ISO 8601 duration, prefixed with
+// a minus sign when negative.
+func isoSeconds(n int) string {
+	if n < 0 {
+		return "-PT" + strconv.Itoa(-n) + "S"
+	}
+	return "PT" + strconv.Itoa(n) + "S"
+}
+
+// Explain returns a breakdown of the bit fields of ip, mirroring the
+// diagram of the specification:
+//
+//	244.23.35.255 = 0xf41723ff
+//	bits 31-28  class E  0xf   -> valid
+//	bits 27-17  month    523   -> June 2015
+//	bits 16-15  d        2     -> +1
+//	bits 14-8   dTAI     35    -> UTC = TAI - 35 sec
+//	bits 7-0    CRC-8    0xff  -> valid
+//
+// An address that doesn't parse returns the malformed address (-6) error.
+// Otherwise the breakdown is returned even if ip doesn't decode, along
+// with the decode error.
+func Explain(ip string) (string, error) {
+	u, err := parseIPv4(ip)
+	if err != nil {
+		return "", &Error{Code: CodeMalformedAddress, IP: ip, Err: err}
+	}
+	_, err = Decode(ip)
+
+	valid := func(ok bool) string {
+		if ok {
+			return "valid"
+		}
+		return "invalid"
+	}
+	e := u >> 28
+	m := (u >> 17) & 0x7ff
+	d := (u >> 15) & 3
+	o := (u >> 8) & 0x7f
+	c := u & 0xff
+
+	mn := int(m) + 10
+	horizon := Result{Year: 1971 + mn/12, Month: 1 + mn%12}.HumanHorizon()
+	if m == 0 {
+		horizon += " (before epoch)"
+	}
+	action := [...]string{"0", "-1", "+1", "illegal"}[d]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s = 0x%08x\n", ip, u)
+	fmt.Fprintf(&b, "bits 31-28  class E  %-5s -> %s\n", fmt.Sprintf("0x%x", e), valid(e == 0xf))
+	fmt.Fprintf(&b, "bits 27-17  month    %-5d -> %s\n", m, horizon)
+	fmt.Fprintf(&b, "bits 16-15  d        %-5d -> %s\n", d, action)
+	fmt.Fprintf(&b, "bits 14-8   dTAI     %-5d -> UTC = TAI - %d sec\n", o, o)
+	fmt.Fprintf(&b, "bits 7-0    CRC-8    %-5s -> %s\n", fmt.Sprintf("0x%02x", c), valid(crc8(u) == 0x80))
+	return b.String(), err
+}
@@ -0,0 +1,146 @@
+package dnsleapsecs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResultHumanHorizon(t *testing.T) {
+	tests := []struct {
+		r         Result
+		month, hh string
+	}{
+		{Result{2015, 6, 35, +1, 2}, "June", "June 2015"},
+		{Result{1971, 12, 9, +1, 2}, "December", "December 1971"},
+		{Result{2135, 1, 72, -1, 1}, "January", "January 2135"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.MonthName(); got != tt.month {
+			t.Errorf("got %q, want: %q", got, tt.month)
+		}
+		if got := tt.r.TimeMonth(); got != time.Month(tt.r.Month) {
+			t.Errorf("got %v, want: %v", got, time.Month(tt.r.Month))
+		}
+		if got := tt.r.HumanHorizon(); got != tt.hh {
+			t.Errorf("got %q, want: %q", got, tt.hh)
+		}
+	}
+}
+
+func TestResultStructuredData(t *testing.T) {
+	tests := []struct {
+		r    Result
+		want string
+	}{
+		{Result{2015, 6, 35, +1, 2}, "dtai=35 delta=1 direction=positive horizon=2015-06"},
+		{Result{1993, 12, 28, 0, 0}, "dtai=28 delta=0 direction=none horizon=1993-12"},
+		{Result{2135, 1, 72, -1, 1}, "dtai=72 delta=-1 direction=negative horizon=2135-01"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.StructuredData(); got != tt.want {
+			t.Errorf("got %q, want: %q", got, tt.want)
+		}
+	}
+}
+
+func TestResultString(t *testing.T) {
+	tests := []struct {
+		r    Result
+		want string
+	}{
+		{Result{2015, 6, 35, +1, 2}, "2015-06 dTAI=35 (UTC=TAI-35s), +1 at month end"},
+		{Result{2135, 1, 72, -1, 1}, "2135-01 dTAI=72 (UTC=TAI-72s), -1 at month end"},
+		{Result{1993, 12, 28, 0, 0}, "1993-12 dTAI=28 (UTC=TAI-28s), no change"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.String(); got != tt.want {
+			t.Errorf("got %q, want: %q", got, tt.want)
+		}
+		if got := fmt.Sprint(tt.r); got != tt.want {
+			t.Errorf("%%v: got %q, want: %q", got, tt.want)
+		}
+	}
+}
+
+func TestAnnouncementString(t *testing.T) {
+	leap := time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		a    Announcement
+		want string
+	}{
+		{
+			Result{2015, 6, 35, +1, 2}.Describe(leap.Add(-time.Second)),
+			"2015-06 dTAI=35 (UTC=TAI-35s), +1 at month end; offset 35, leap pending at 2015-07-01T00:00:00Z",
+		},
+		{
+			Result{2015, 6, 35, +1, 2}.Describe(leap),
+			"2015-06 dTAI=35 (UTC=TAI-35s), +1 at month end; offset 36, leap passed at 2015-07-01T00:00:00Z",
+		},
+		{
+			Result{1993, 12, 28, 0, 0}.Describe(leap),
+			"1993-12 dTAI=28 (UTC=TAI-28s), no change; offset 28",
+		},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(tt.a); got != tt.want {
+			t.Errorf("got %q, want: %q", got, tt.want)
+		}
+	}
+}
+
+func TestResultOffsetISO(t *testing.T) {
+	tests := []struct {
+		r            Result
+		offset, post string
+	}{
+		{Result{2015, 6, 35, +1, 2}, "-PT35S", "-PT36S"},
+		{Result{2135, 1, 72, -1, 1}, "-PT72S", "-PT71S"},
+		{Result{1971, 12, 0, 0, 0}, "PT0S", "PT0S"},
+		{Result{1971, 12, -5, -1, 1}, "PT5S", "PT6S"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.OffsetISO(); got != tt.offset {
+			t.Errorf("%#v: got %q, want: %q", tt.r, got, tt.offset)
+		}
+		if got := tt.r.OffsetAfterHorizonISO(); got != tt.post {
+			t.Errorf("%#v: got %q, want: %q", tt.r, got, tt.post)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	got, err := Explain("244.23.35.255")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	want := `244.23.35.255 = 0xf41723ff
+bits 31-28  class E  0xf   -> valid
+bits 27-17  month    523   -> June 2015
+bits 16-15  d        2     -> +1
+bits 14-8   dTAI     35    -> UTC = TAI - 35 sec
+bits 7-0    CRC-8    0xff  -> valid
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	got, err = Explain("255.209.76.40")
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -2 {
+		t.Errorf("got %#v, want code -2", err)
+	}
+	if !strings.Contains(got, "CRC-8    0x28  -> invalid") {
+		t.Errorf("got:\n%s\nwant invalid CRC-8", got)
+	}
+
+	got, err = Explain("not.an.ip.address")
+	if !errors.As(err, &e) || e.Code != -6 || e.IP != "not.an.ip.address" {
+		t.Errorf("got %#v, want code -6 for the address", err)
+	}
+	if got != "" {
+		t.Errorf("got %q", got)
+	}
+}
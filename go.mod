@@ -1,3 +1,3 @@
 module github.com/dwlnetnl/dnsleapsecs
 
-go 1.16
+go 1.20
@@ -0,0 +1,280 @@
+package dnsleapsecs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// history lists every change of TAI-UTC since UTC got an integral
+// offset on 1 January 1972, each entry holding the first month the
+// new dTAI is in effect. Source: IERS Bulletin C.
+var history = []struct {
+	year, month int
+	dtai        int
+}{
+	{1972, 1, 10},
+	{1972, 7, 11},
+	{1973, 1, 12},
+	{1974, 1, 13},
+	{1975, 1, 14},
+	{1976, 1, 15},
+	{1977, 1, 16},
+	{1978, 1, 17},
+	{1979, 1, 18},
+	{1980, 1, 19},
+	{1981, 7, 20},
+	{1982, 7, 21},
+	{1983, 7, 22},
+	{1985, 7, 23},
+	{1988, 1, 24},
+	{1990, 1, 25},
+	{1991, 1, 26},
+	{1992, 7, 27},
+	{1993, 7, 28},
+	{1994, 7, 29},
+	{1996, 1, 30},
+	{1997, 7, 31},
+	{1999, 1, 32},
+	{2006, 1, 33},
+	{2009, 1, 34},
+	{2012, 7, 35},
+	{2015, 7, 36},
+	{2017, 1, 37},
+}
+
+// historyYear and historyMonth is the horizon up to which history is
+// known to be complete, as announced by IERS Bulletin C 70 (July 2025).
+const (
+	historyYear  = 2025
+	historyMonth = 12
+)
+
+// ErrOutsideHistory is returned when a time falls outside the range
+// covered by the embedded historical table.
+var ErrOutsideHistory = errors.New("outside historical table")
+
+// ExpectedCurrent returns the announcement expected to be published at
+// now, synthesized from the embedded historical table, and its encoding.
+// It is meant for validating what DNS returns without network access.
+//
+// The horizon follows the Bulletin C cadence, see NextPublicationWindow:
+// during a publication window the end of the fifth month after its start
+// is announced, otherwise the end of the month before the next window.
+// The table can't predict leap-seconds announced after it was last
+// updated, past its horizon the latest announcement in the table is
+// returned. ErrOutsideHistory is returned for times before 1972.
+func ExpectedCurrent(now time.Time) (string, Result, error) {
+	r, err := expected(now)
+	if err != nil {
+		return "", Result{}, err
+	}
+	ip, err := Encode(r)
+	if err != nil {
+		return "", Result{}, err
+	}
+	return ip, r, nil
+}
+
+func expected(now time.Time) (Result, error) {
+	if first := history[0]; now.Before(monthStart(first.year, first.month)) {
+		return Result{}, fmt.Errorf("%w: %s", ErrOutsideHistory, now.UTC().Format(time.RFC3339))
+	}
+	start, _ := NextPublicationWindow(now)
+	horizon := start.AddDate(0, -1, 0)
+	if !now.Before(start) {
+		horizon = start.AddDate(0, 5, 0)
+	}
+	year, month := horizon.Year(), int(horizon.Month())
+	if MonthsSince1971(year, month) > MonthsSince1971(historyYear, historyMonth) {
+		return historical(), nil
+	}
+	dtai := DTAIAt(monthStart(year, month))
+	delta := DTAIAt(monthStart(year, month+1)) - dtai
+	return Result{
+		Year:   year,
+		Month:  month,
+		DTAI:   dtai,
+		Delta:  delta,
+		Action: int(actionCode(delta)),
+	}, nil
+}
+
+// OffsetDelta returns the number of leap-seconds accumulated between a
+// and b according to the embedded historical table, negative if b is
+// before a. Before 1972 the offset is taken as zero, after the table's
+// horizon as its latest offset.
+func OffsetDelta(a, b time.Time) int {
+	return DTAIAt(b) - DTAIAt(a)
+}
+
+// LeapsBetween returns the leap-seconds in the embedded historical table
+// falling in [start, end), in chronological order. A leap-second is
+// represented by the instant it ends, the first instant of the month
+// following it, which is also what 23:59:60 normalizes to in Go. The
+// initial offset of 1 January 1972 isn't a leap-second.
+func LeapsBetween(start, end time.Time) []time.Time {
+	var leaps []time.Time
+	for _, h := range history[1:] {
+		t := monthStart(h.year, h.month)
+		if !t.Before(start) && t.Before(end) {
+			leaps = append(leaps, t)
+		}
+	}
+	return leaps
+}
+
+// IsDuringLeap reports whether t falls within an inserted leap-second
+// according to the embedded historical table. Go normalizes 23:59:60
+// to the first second of the following month, so a leap-second covers
+// [00:00:00, 00:00:01) UTC of the month after it was inserted, the same
+// instant LeapsBetween reports. Time values can't tell a normalized
+// 23:59:60 apart from a genuine 00:00:00, both are reported as being
+// during the leap-second.
+//
+// ErrOutsideHistory is returned for times before 1972 or past the
+// table's horizon.
+func IsDuringLeap(t time.Time) (bool, error) {
+	if err := checkHistory(t); err != nil {
+		return false, err
+	}
+	for i := 1; i < len(history); i++ {
+		h := history[i]
+		if h.dtai <= history[i-1].dtai {
+			continue // no second inserted
+		}
+		start := monthStart(h.year, h.month)
+		if !t.Before(start) && t.Before(start.Add(time.Second)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkHistory returns ErrOutsideHistory if t isn't covered by the
+// historical table.
+func checkHistory(t time.Time) error {
+	first := history[0]
+	if t.Before(monthStart(first.year, first.month)) ||
+		!t.Before(monthStart(historyYear, historyMonth+1)) {
+		return fmt.Errorf("%w: %s", ErrOutsideHistory, t.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// AgreesWithHistory reports whether r is consistent with the embedded
+// historical table, and if not, why. It catches stale or spoofed records
+// that pass the CRC-8 check but describe an impossible offset.
+//
+// For a horizon covered by the table, DTAI must equal the offset in
+// effect during the announced month and DTAI+Delta the offset after it.
+// As the table starts in 1972, DTAI of December 1971 isn't checked.
+// Past the table's horizon, DTAI may differ from the latest offset by
+// no more than the number of month ends in between, each being a leap
+// opportunity as in SanityCheck.
+func (r Result) AgreesWithHistory() (bool, string) {
+	first := history[0]
+	start, end := r.Window()
+	if end.Before(monthStart(first.year, first.month)) {
+		return false, fmt.Sprintf("horizon %04d-%02d before historical table", r.Year, r.Month)
+	}
+	if end.After(monthStart(historyYear, historyMonth+1)) {
+		if err := SanityCheck(historical(), r); err != nil {
+			return false, err.Error()
+		}
+		return true, ""
+	}
+	if dtai := DTAIAt(start); !start.Before(monthStart(first.year, first.month)) && r.DTAI != dtai {
+		return false, fmt.Sprintf("dtai %d in %04d-%02d, history has %d", r.DTAI, r.Year, r.Month, dtai)
+	}
+	if dtai := DTAIAt(end); r.OffsetAfterHorizon() != dtai {
+		return false, fmt.Sprintf("dtai %d after %04d-%02d, history has %d",
+			r.OffsetAfterHorizon(), r.Year, r.Month, dtai)
+	}
+	return true, ""
+}
+
+// DTAIAt returns the TAI-UTC offset in effect at utc according to the
+// embedded historical table: zero before 1972 and the latest offset in
+// the table after its horizon, as the table can't know about
+// leap-seconds announced after it was last updated.
+func DTAIAt(utc time.Time) int {
+	dtai := 0
+	for _, h := range history {
+		if utc.Before(monthStart(h.year, h.month)) {
+			break
+		}
+		dtai = h.dtai
+	}
+	return dtai
+}
+
+// FetchOrHistorical is like Fetch, but when the lookup fails or the
+// response is empty, also when reported as ErrNoAnnouncement, it falls
+// back to the latest announcement in the embedded historical table,
+// reporting it did so. Decode errors aren't recovered from as they may
+// indicate tampering.
+//
+// The fallback can't know about leap-seconds announced after the table
+// was last updated, see AllOffsets.
+func FetchOrHistorical(ctx context.Context, opts ...Option) (Result, bool, error) {
+	_, r, err := Fetch(ctx, opts...)
+	var e *Error
+	if errors.Is(err, ErrNoAnnouncement) ||
+		errors.As(err, &e) && (e.Code == CodeLookupFailed || e.Code == CodeEmptyResponse) {
+		return historical(), true, nil
+	}
+	return r, false, err
+}
+
+// BulletinC returns the number of the IERS Bulletin C announcing r's
+// horizon, 0 if there is none. Bulletin C is published every six months,
+// in January announcing the end of June and in July announcing the end
+// of December. The numbering is aligned on Bulletin C 49 (January 2015)
+// announcing June 2015 and extrapolated backwards at the same cadence,
+// so horizons other than June and December, and those before Bulletin
+// C 1 (June 1991), return 0.
+func (r Result) BulletinC() int {
+	if r.Month != 6 && r.Month != 12 {
+		return 0
+	}
+	n := 49 + (MonthsSince1971(r.Year, r.Month)-MonthsSince1971(2015, 6))/6
+	if n < 1 {
+		return 0
+	}
+	return n
+}
+
+// historical returns the latest announcement in the historical table.
+func historical() Result {
+	return Result{
+		Year:  historyYear,
+		Month: historyMonth,
+		DTAI:  history[len(history)-1].dtai,
+	}
+}
+
+// monthStart returns the first instant of the given UTC month,
+// month is normalized like time.Date does.
+func monthStart(year, month int) time.Time {
+	return time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Offset is a TAI-UTC step.
+type Offset struct {
+	From time.Time // first instant in UTC the offset is in effect
+	DTAI int
+}
+
+// AllOffsets returns every TAI-UTC step since 1 January 1972 in
+// chronological order. The table is embedded in the package, taken
+// from IERS Bulletin C and last updated with Bulletin C 70 (July 2025)
+// which announced no leap-second at the end of December 2025.
+func AllOffsets() []Offset {
+	offsets := make([]Offset, len(history))
+	for i, h := range history {
+		offsets[i] = Offset{From: monthStart(h.year, h.month), DTAI: h.dtai}
+	}
+	return offsets
+}
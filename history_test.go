@@ -0,0 +1,308 @@
+package dnsleapsecs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	for i := 1; i < len(history); i++ {
+		prev, h := history[i-1], history[i]
+		if !monthStart(prev.year, prev.month).Before(monthStart(h.year, h.month)) {
+			t.Errorf("entry %d not in chronological order", i)
+		}
+		if h.dtai-prev.dtai != 1 {
+			t.Errorf("entry %d: got step %d, want: 1", i, h.dtai-prev.dtai)
+		}
+	}
+}
+
+func TestExpectedCurrent(t *testing.T) {
+	tests := []struct {
+		now    time.Time
+		ip     string
+		result Result
+	}{
+		{time.Date(1972, 3, 1, 0, 0, 0, 0, time.UTC), "240.15.10.108", Result{1972, 6, 10, +1, 2}},
+		{time.Date(2015, 1, 5, 0, 0, 0, 0, time.UTC), "244.23.35.255", Result{2015, 6, 35, +1, 2}},
+		{time.Date(2015, 6, 30, 23, 59, 59, 0, time.UTC), "244.23.35.255", Result{2015, 6, 35, +1, 2}},
+		{time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC), "", Result{2015, 12, 36, 0, 0}},
+		{time.Date(2016, 7, 6, 0, 0, 0, 0, time.UTC), "", Result{2016, 12, 36, +1, 2}},
+		{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), "", Result{2017, 6, 37, 0, 0}},
+
+		// No leap-second in this stretch: the horizon still advances
+		// with every Bulletin C.
+		{time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC), "", Result{2019, 12, 37, 0, 0}},
+		{time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), "", Result{2020, 6, 37, 0, 0}},
+		{time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC), "", Result{2020, 6, 37, 0, 0}},
+		{time.Date(2020, 8, 15, 0, 0, 0, 0, time.UTC), "", Result{2020, 12, 37, 0, 0}},
+
+		// Past the table's horizon its latest announcement is returned.
+		{time.Date(historyYear, historyMonth, 31, 0, 0, 0, 0, time.UTC), "", Result{2025, 12, 37, 0, 0}},
+		{time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), "", Result{2025, 12, 37, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.now.Format("2006-01-02"), func(t *testing.T) {
+			ip, r, err := ExpectedCurrent(tt.now)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if r != tt.result {
+				t.Errorf("got %#v, want: %#v", r, tt.result)
+			}
+			if tt.ip != "" && ip != tt.ip {
+				t.Errorf("got %q, want: %q", ip, tt.ip)
+			}
+			if got, err := Decode(ip); err != nil || got != r {
+				t.Errorf("got %#v (%v), want: %#v", got, err, r)
+			}
+		})
+	}
+
+	now := time.Date(1971, 12, 31, 0, 0, 0, 0, time.UTC)
+	if _, _, err := ExpectedCurrent(now); !errors.Is(err, ErrOutsideHistory) {
+		t.Errorf("%v: got %v, want: %v", now, err, ErrOutsideHistory)
+	}
+}
+
+func TestAllOffsets(t *testing.T) {
+	offsets := AllOffsets()
+	if len(offsets) != len(history) {
+		t.Fatalf("got %d offsets, want: %d", len(offsets), len(history))
+	}
+	first := Offset{time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC), 10}
+	if offsets[0] != first {
+		t.Errorf("got %v, want: %v", offsets[0], first)
+	}
+	last := Offset{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 37}
+	if offsets[len(offsets)-1] != last {
+		t.Errorf("got %v, want: %v", offsets[len(offsets)-1], last)
+	}
+
+	// Modifying the returned slice doesn't affect the table.
+	offsets[0].DTAI = 0
+	if AllOffsets()[0].DTAI != 10 {
+		t.Error("table modified")
+	}
+}
+
+func TestFetchOrHistorical(t *testing.T) {
+	defer func(r Resolver) { defaultResolver = r }(defaultResolver)
+	ctx := context.Background()
+
+	defaultResolver = testResolver{addr: "244.23.35.255"}
+	r, fallback, err := FetchOrHistorical(ctx)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if fallback || r != (Result{2015, 6, 35, +1, 2}) {
+		t.Errorf("got (%#v, %t)", r, fallback)
+	}
+
+	want := Result{historyYear, historyMonth, 37, 0, 0}
+	for _, tr := range []testResolver{
+		{err: errors.New("some lookup error")},
+		{},
+	} {
+		defaultResolver = tr
+		r, fallback, err := FetchOrHistorical(ctx)
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if !fallback || r != want {
+			t.Errorf("got (%#v, %t), want: (%#v, true)", r, fallback, want)
+		}
+	}
+
+	defaultResolver = testResolver{}
+	r, fallback, err = FetchOrHistorical(ctx, NoAnnouncement())
+	if err != nil || !fallback || r != want {
+		t.Errorf("NoAnnouncement: got (%#v, %t, %v), want: (%#v, true, <nil>)", r, fallback, err, want)
+	}
+
+	defaultResolver = testResolver{addr: "255.209.76.40"}
+	_, fallback, err = FetchOrHistorical(ctx)
+	var e *Error
+	if fallback || !errors.As(err, &e) || e.Code != -2 {
+		t.Errorf("got (%t, %#v), want code -2", fallback, err)
+	}
+}
+
+func TestOffsetDelta(t *testing.T) {
+	date := func(year, month, day int) time.Time {
+		return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		a, b time.Time
+		want int
+	}{
+		{date(2000, 1, 1), date(2020, 1, 1), 5}, // 2005, 2008, 2012, 2015, 2016
+		{date(2020, 1, 1), date(2000, 1, 1), -5},
+		{date(2015, 6, 30), date(2015, 7, 1), 1},
+		{date(2015, 7, 1), date(2016, 12, 31), 0},
+		{date(1972, 1, 1), date(2017, 1, 1), 27},
+		{date(1970, 1, 1), date(1972, 1, 1), 10},
+	}
+	for _, tt := range tests {
+		if got := OffsetDelta(tt.a, tt.b); got != tt.want {
+			t.Errorf("%v to %v: got %d, want: %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDTAIAt(t *testing.T) {
+	tests := []struct {
+		utc  time.Time
+		want int
+	}{
+		{time.Date(1971, 12, 31, 23, 59, 59, 0, time.UTC), 0},
+		{time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC), 10},
+		{time.Date(1972, 6, 30, 0, 0, 0, 0, time.UTC), 10},
+		{time.Date(1972, 7, 1, 0, 0, 0, 0, time.UTC), 11},
+		{time.Date(2012, 7, 1, 0, 0, 0, 0, time.UTC), 35},
+		{time.Date(2015, 6, 30, 23, 59, 59, 0, time.UTC), 35},
+		{time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC), 36},
+		{time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), 37},
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 37},
+	}
+	for _, tt := range tests {
+		if got := DTAIAt(tt.utc); got != tt.want {
+			t.Errorf("%v: got %d, want: %d", tt.utc, got, tt.want)
+		}
+	}
+
+	// Every change in the table round-trips through the encoding as the
+	// announcement of the month before.
+	for _, h := range history[1:] {
+		end := monthStart(h.year, h.month).Add(-time.Second)
+		r := Result{end.Year(), int(end.Month()), DTAIAt(end), +1, 2}
+		ip, err := Encode(r)
+		if err != nil {
+			t.Errorf("%#v: got error: %v", r, err)
+			continue
+		}
+		if got, err := Decode(ip); err != nil || got != r || got.OffsetAfterHorizon() != h.dtai {
+			t.Errorf("%s: got (%#v, %v), want: %#v", ip, got, err, r)
+		}
+	}
+}
+
+func TestLeapsBetween(t *testing.T) {
+	date := func(year, month, day int) time.Time {
+		return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		start, end time.Time
+		want       []time.Time
+	}{
+		{date(2015, 1, 1), date(2018, 1, 1), []time.Time{date(2015, 7, 1), date(2017, 1, 1)}},
+		{date(2015, 7, 1), date(2017, 1, 1), []time.Time{date(2015, 7, 1)}},
+		{date(1999, 1, 2), date(2005, 12, 31), nil},
+		{date(1970, 1, 1), date(1972, 7, 1), nil},
+	}
+	for _, tt := range tests {
+		got := LeapsBetween(tt.start, tt.end)
+		if len(got) != len(tt.want) {
+			t.Errorf("[%v, %v): got %v, want: %v", tt.start, tt.end, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("[%v, %v): got %v, want: %v", tt.start, tt.end, got, tt.want)
+			}
+		}
+	}
+	if got := len(LeapsBetween(date(1970, 1, 1), date(2100, 1, 1))); got != 27 {
+		t.Errorf("got %d leap-seconds, want: 27", got)
+	}
+}
+
+func TestIsDuringLeap(t *testing.T) {
+	leap := time.Date(2016, 12, 31, 23, 59, 60, 0, time.UTC)
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{leap, true},
+		{leap.Add(999 * time.Millisecond), true},
+		{leap.Add(time.Second), false},
+		{leap.Add(-time.Nanosecond), false},
+		{time.Date(2015, 6, 30, 23, 59, 60, 500, time.UTC), true},
+		{time.Date(2015, 6, 30, 12, 0, 0, 0, time.UTC), false},
+		{time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC), false}, // initial offset
+	}
+	for _, tt := range tests {
+		got, err := IsDuringLeap(tt.t)
+		if err != nil {
+			t.Fatalf("%v: got error: %v", tt.t, err)
+		}
+		if got != tt.want {
+			t.Errorf("%v: got %t, want: %t", tt.t, got, tt.want)
+		}
+	}
+
+	for _, tm := range []time.Time{
+		time.Date(1971, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(historyYear, historyMonth+1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		if _, err := IsDuringLeap(tm); !errors.Is(err, ErrOutsideHistory) {
+			t.Errorf("%v: got %v, want: %v", tm, err, ErrOutsideHistory)
+		}
+	}
+}
+
+func TestResultAgreesWithHistory(t *testing.T) {
+	tests := []struct {
+		r    Result
+		want bool
+	}{
+		{Result{1971, 12, 9, +1, 2}, true},
+		{Result{2015, 6, 35, +1, 2}, true},
+		{Result{2016, 12, 36, +1, 2}, true},
+		{Result{1993, 12, 28, 0, 0}, true},
+		{Result{2025, 12, 37, 0, 0}, true},
+		{Result{2015, 6, 36, 0, 0}, false},   // stale offset
+		{Result{2015, 6, 35, 0, 0}, false},   // leap missing
+		{Result{2015, 12, 35, +1, 2}, false}, // leap already happened
+		{Result{1971, 12, 9, 0, 0}, false},
+		{Result{2026, 6, 37, +1, 2}, true},
+		{Result{2026, 6, 38, 0, 0}, true},  // one month end in between
+		{Result{2026, 6, 36, -1, 1}, true}, // negative leap
+		{Result{2026, 1, 38, 0, 0}, false},
+		{Result{2026, 6, 43, 0, 0}, false},
+	}
+	for _, tt := range tests {
+		got, reason := tt.r.AgreesWithHistory()
+		if got != tt.want {
+			t.Errorf("%#v: got %t (%s), want: %t", tt.r, got, reason, tt.want)
+		}
+		if got != (reason == "") {
+			t.Errorf("%#v: got reason %q", tt.r, reason)
+		}
+	}
+}
+
+func TestResultBulletinC(t *testing.T) {
+	tests := []struct {
+		r    Result
+		want int
+	}{
+		{Result{2015, 6, 35, +1, 2}, 49},
+		{Result{2016, 12, 36, +1, 2}, 52},
+		{Result{2025, 12, 37, 0, 0}, 70},
+		{Result{1991, 6, 26, 0, 0}, 1},
+		{Result{1990, 12, 25, 0, 0}, 0},
+		{Result{1993, 12, 28, 0, 0}, 6},
+		{Result{2015, 7, 36, 0, 0}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.r.BulletinC(); got != tt.want {
+			t.Errorf("%04d-%02d: got %d, want: %d", tt.r.Year, tt.r.Month, got, tt.want)
+		}
+	}
+	if got := historical().BulletinC(); got != 70 {
+		t.Errorf("got %d, want: 70", got)
+	}
+}
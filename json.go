@@ -0,0 +1,78 @@
+package dnsleapsecs
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// jsonResult is the JSON form of a Result.
+type jsonResult struct {
+	Year      int `json:"year"`
+	Month     int `json:"month"`
+	DTAI      int `json:"dtai"`
+	Delta     int `json:"delta"`
+	UTCOffset int `json:"utc_offset"`
+}
+
+// MarshalJSON implements json.Marshaler. The object has the fields year,
+// month, dtai and delta, and the derived utc_offset, which is -dtai as in
+// UTC - TAI = -dTAI:
+//
+//	{"year":2015,"month":6,"dtai":35,"delta":1,"utc_offset":-35}
+func (r Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.json())
+}
+
+// json returns the JSON form of r.
+func (r Result) json() jsonResult {
+	return jsonResult{
+		Year:      r.Year,
+		Month:     r.Month,
+		DTAI:      r.DTAI,
+		Delta:     r.Delta,
+		UTCOffset: -r.DTAI,
+	}
+}
+
+// MarshalJSON implements json.Marshaler. It overrides the one of the
+// embedded Result, adding the derived fields offset, horizon, leap and
+// pending to its object. leap is omitted when no leap-second is
+// announced:
+//
+//	{"year":2015,"month":6,"dtai":35,"delta":1,"utc_offset":-35,"offset":35,
+//	 "horizon":"2015-07-01T00:00:00Z","leap":"2015-07-01T00:00:00Z","pending":true}
+func (a Announcement) MarshalJSON() ([]byte, error) {
+	var leap *time.Time
+	if !a.Leap.IsZero() {
+		leap = &a.Leap
+	}
+	return json.Marshal(struct {
+		jsonResult
+		Offset  int        `json:"offset"`
+		Horizon time.Time  `json:"horizon"`
+		Leap    *time.Time `json:"leap,omitempty"`
+		Pending bool       `json:"pending"`
+	}{a.Result.json(), a.Offset, a.Horizon, leap, a.Pending})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Action is derived from
+// delta and the utc_offset field is ignored. A Result that doesn't
+// Validate returns its invalid result (-20) error.
+func (r *Result) UnmarshalJSON(b []byte) error {
+	var j jsonResult
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	res := Result{
+		Year:   j.Year,
+		Month:  j.Month,
+		DTAI:   j.DTAI,
+		Delta:  j.Delta,
+		Action: int(actionCode(j.Delta)),
+	}
+	if err := res.Validate(); err != nil {
+		return err
+	}
+	*r = res
+	return nil
+}
@@ -0,0 +1,83 @@
+package dnsleapsecs
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResultJSON(t *testing.T) {
+	b, err := json.Marshal(Result{2015, 6, 35, +1, 2})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if want := `{"year":2015,"month":6,"dtai":35,"delta":1,"utc_offset":-35}`; string(b) != want {
+		t.Errorf("got %s, want: %s", b, want)
+	}
+
+	for _, tv := range TestVectors {
+		if tv.Err != nil {
+			continue
+		}
+		b, err := json.Marshal(tv.Result)
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		var r Result
+		if err := json.Unmarshal(b, &r); err != nil {
+			t.Fatalf("%s: got error: %v", b, err)
+		}
+		if r != tv.Result {
+			t.Errorf("got %#v, want: %#v", r, tv.Result)
+		}
+	}
+
+	for _, s := range []string{
+		`{"year":2015,"month":0,"dtai":35,"delta":1}`,
+		`{"year":2015,"month":13,"dtai":35,"delta":1}`,
+		`{"year":2015,"month":6,"dtai":35,"delta":2}`,
+		`{"year":2015,"month":6,"dtai":35,"delta":-2}`,
+		`{"year":2015,"month":6,"dtai":128,"delta":0}`,
+		`{"year":2015,"month":6,"dtai":-1,"delta":0}`,
+		`{"year":1971,"month":11,"dtai":9,"delta":0}`,
+		`{"year":2143,"month":1,"dtai":37,"delta":0}`,
+	} {
+		var r Result
+		err := json.Unmarshal([]byte(s), &r)
+		if !errors.Is(err, ErrInvalidResult) {
+			t.Errorf("%s: got %v, want code -20", s, err)
+		}
+		if r != (Result{}) {
+			t.Errorf("%s: got %#v", s, r)
+		}
+	}
+
+	var r Result
+	if err := json.Unmarshal([]byte(`{"year":"2015"}`), &r); err == nil || errors.Is(err, ErrInvalidResult) {
+		t.Errorf("got %v, want JSON error", err)
+	}
+}
+
+func TestAnnouncementJSON(t *testing.T) {
+	leap := time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC)
+	b, err := json.Marshal(Result{2015, 6, 35, +1, 2}.Describe(leap.Add(-time.Second)))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	want := `{"year":2015,"month":6,"dtai":35,"delta":1,"utc_offset":-35,"offset":35,` +
+		`"horizon":"2015-07-01T00:00:00Z","leap":"2015-07-01T00:00:00Z","pending":true}`
+	if string(b) != want {
+		t.Errorf("got %s, want: %s", b, want)
+	}
+
+	b, err = json.Marshal(Result{1993, 12, 28, 0, 0}.Describe(leap))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	want = `{"year":1993,"month":12,"dtai":28,"delta":0,"utc_offset":-28,"offset":28,` +
+		`"horizon":"1994-01-01T00:00:00Z","pending":false}`
+	if string(b) != want {
+		t.Errorf("got %s, want: %s", b, want)
+	}
+}
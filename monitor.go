@@ -0,0 +1,145 @@
+package dnsleapsecs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EventKind is the kind of an Event reported by a Monitor.
+type EventKind int
+
+// Event kinds.
+const (
+	// EventAnnouncement reports the first announcement fetched and every
+	// change to it afterwards.
+	EventAnnouncement EventKind = iota
+
+	// EventError reports a failed lookup.
+	EventError
+
+	// EventMissedWindow reports a publication window, see
+	// NextPublicationWindow, passed without the horizon advancing to
+	// the one the Bulletin C published in it would announce. IERS may
+	// have published something the zone hasn't picked up yet.
+	EventMissedWindow
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventAnnouncement:
+		return "announcement"
+	case EventError:
+		return "error"
+	case EventMissedWindow:
+		return "missed window"
+	}
+	return "EventKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Event is reported by a Monitor.
+type Event struct {
+	Kind   EventKind
+	Time   time.Time // when the event occurred
+	Result Result    // latest announcement, zero if none was fetched yet
+	Err    error     // set for EventError and EventMissedWindow
+}
+
+// Monitor periodically looks up the announcement, reporting changes,
+// failures and missed publication windows as events.
+type Monitor struct {
+	r        Resolver
+	interval time.Duration
+	opts     []Option
+	events   chan Event
+	now      func() time.Time
+
+	mu     sync.Mutex
+	last   *Result
+	warned time.Time // start of the last window reported missed
+}
+
+// NewMonitor returns a Monitor looking up the DefaultHost record using r
+// every interval, decoding with opts.
+func NewMonitor(r Resolver, interval time.Duration, opts ...Option) *Monitor {
+	return &Monitor{
+		r:        r,
+		interval: interval,
+		opts:     opts,
+		events:   make(chan Event, 16),
+		now:      time.Now,
+	}
+}
+
+// Events returns the channel events are delivered on. Delivery doesn't
+// block: when the channel buffer is full, an event is dropped.
+func (m *Monitor) Events() <-chan Event {
+	return m.events
+}
+
+// Run looks up the announcement right away and then every interval,
+// until ctx is done. It returns the error of ctx. Like with Fetch, a
+// lookup times out after DefaultFetchTimeout when ctx has no deadline,
+// so that a hung resolver doesn't stall the Monitor.
+func (m *Monitor) Run(ctx context.Context) error {
+	t := time.NewTicker(m.interval)
+	defer t.Stop()
+	for {
+		m.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// poll looks up the announcement once and reports events.
+func (m *Monitor) poll(ctx context.Context) {
+	ctx, cancel := fetchContext(ctx)
+	defer cancel()
+	_, r, err := Lookup(ctx, m.r, m.opts...)
+	now := m.now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		var last Result
+		if m.last != nil {
+			last = *m.last
+		}
+		m.send(Event{Kind: EventError, Time: now, Result: last, Err: err})
+		return
+	}
+	if m.last == nil || !m.last.Equal(r) {
+		m.last = &r
+		m.send(Event{Kind: EventAnnouncement, Time: now, Result: r})
+	}
+
+	// The Bulletin C published in the window before the current or next
+	// one announces the end of the fifth month after its start: June
+	// for January and December for July.
+	start, _ := NextPublicationWindow(now)
+	prev, _ := NextPublicationWindow(start.AddDate(0, -6, 0))
+	want := MonthsSince1971(prev.Year(), int(prev.Month())+5)
+	if MonthsSince1971(r.Year, r.Month) < want && !m.warned.Equal(prev) {
+		m.warned = prev
+		m.send(Event{
+			Kind:   EventMissedWindow,
+			Time:   now,
+			Result: r,
+			Err: fmt.Errorf("horizon %04d-%02d not advanced after publication window %s",
+				r.Year, r.Month, prev.Format("2006-01")),
+		})
+	}
+}
+
+// send delivers e without blocking. m.mu must be held.
+func (m *Monitor) send(e Event) {
+	select {
+	case m.events <- e:
+	default:
+	}
+}
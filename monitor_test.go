@@ -0,0 +1,111 @@
+package dnsleapsecs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMonitor(t *testing.T) {
+	ctx := context.Background()
+	encode := func(r Result) string {
+		ip, err := Encode(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ip
+	}
+	c70 := Result{2025, 12, 37, 0, 0}
+	c71 := Result{2026, 6, 37, 0, 0}
+
+	tr := &testResolver{addr: encode(c70)}
+	m := NewMonitor(tr, time.Hour)
+	now := time.Date(2025, 8, 10, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	expect := func(kind EventKind, r Result) {
+		t.Helper()
+		select {
+		case e := <-m.Events():
+			if e.Kind != kind || e.Result != r || !e.Time.Equal(now) {
+				t.Errorf("got %v event %#v at %v, want: %v event %#v", e.Kind, e.Result, e.Time, kind, r)
+			}
+		default:
+			t.Errorf("got no event, want: %v", kind)
+		}
+	}
+	expectNone := func() {
+		t.Helper()
+		select {
+		case e := <-m.Events():
+			t.Errorf("got %v event: %#v", e.Kind, e)
+		default:
+		}
+	}
+
+	m.poll(ctx)
+	expect(EventAnnouncement, c70)
+	m.poll(ctx)
+	expectNone()
+
+	// Bulletin C 71 is due in January 2026.
+	now = time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	m.poll(ctx)
+	expectNone()
+	now = time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	m.poll(ctx)
+	expect(EventMissedWindow, c70)
+	m.poll(ctx)
+	expectNone()
+
+	tr.addr = encode(c71)
+	m.poll(ctx)
+	expect(EventAnnouncement, c71)
+
+	tr.err = errors.New("some lookup error")
+	m.poll(ctx)
+	expect(EventError, c71)
+
+	// Delivery doesn't block.
+	for i := 0; i < cap(m.events)+1; i++ {
+		m.poll(ctx)
+	}
+	if len(m.events) != cap(m.events) {
+		t.Errorf("got %d events, want: %d", len(m.events), cap(m.events))
+	}
+}
+
+func TestMonitorRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewMonitor(testResolver{addr: "244.23.35.255"}, time.Hour)
+	done := make(chan error)
+	go func() { done <- m.Run(ctx) }()
+
+	e := <-m.Events()
+	if e.Kind != EventAnnouncement || e.Result != (Result{2015, 6, 35, +1, 2}) {
+		t.Errorf("got %v event: %#v", e.Kind, e)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("got %v, want: %v", err, context.Canceled)
+	}
+}
+
+func TestMonitorTimeout(t *testing.T) {
+	dr := &deadlineResolver{testResolver: testResolver{addr: "244.23.35.255"}}
+	m := NewMonitor(dr, time.Hour)
+	start := time.Now()
+	m.poll(context.Background())
+	if dr.deadline.Before(start.Add(DefaultFetchTimeout)) || dr.deadline.After(time.Now().Add(DefaultFetchTimeout)) {
+		t.Errorf("got deadline in %v, want: %v", dr.deadline.Sub(start), DefaultFetchTimeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	m = NewMonitor(blockingResolver{}, time.Hour)
+	m.poll(ctx)
+	if e := <-m.Events(); e.Kind != EventError || !errors.Is(e.Err, context.DeadlineExceeded) {
+		t.Errorf("got %v event: %#v", e.Kind, e)
+	}
+}
@@ -0,0 +1,155 @@
+package dnsleapsecs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Option configures Decode and the lookup functions. Options that only
+// concern lookups are ignored by Decode.
+type Option func(*options)
+
+type options struct {
+	tentative      bool
+	noAnnouncement bool
+	strictAction   bool
+	rawAction      bool
+	crc            CRCVerifier
+	baseline       *int
+	ttlBounds      *[2]time.Duration
+	invalidAction  *[]string
+	rejectPast     *time.Time
+	signedDTAI     bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *options) crcVerifier() CRCVerifier {
+	if o.crc == nil {
+		return crc8Verifier{}
+	}
+	return o.crc
+}
+
+// Tentative makes an address failing the CRC-8 check decode into the
+// Result its fields would represent, returned alongside the invalid
+// checksum (-2) error instead of a zero Result. It helps judging if a
+// record is close to valid or garbage. An invalid action decodes into
+// a zero Delta, with Action holding the raw 3.
+func Tentative() Option {
+	return func(o *options) { o.tentative = true }
+}
+
+// ErrNoAnnouncement is returned instead of an empty response (-11) error
+// when the NoAnnouncement option is used.
+var ErrNoAnnouncement = errors.New("no announcement published")
+
+// emptyResponse returns the error for a response without addresses.
+func (o *options) emptyResponse() error {
+	if o.noAnnouncement {
+		return ErrNoAnnouncement
+	}
+	return &Error{Code: CodeEmptyResponse}
+}
+
+// NoAnnouncement makes an empty response return ErrNoAnnouncement, for
+// polling a zone that may legitimately have no record published yet.
+// It distinguishes "nothing published" from real failures.
+func NoAnnouncement() Option {
+	return func(o *options) { o.noAnnouncement = true }
+}
+
+// StrictAction makes a lookup fail immediately on an address with an
+// invalid action (-3), instead of skipping it in search of a valid one.
+// Such an address passed the class E and CRC-8 checks, so it's likely
+// a deliberately published but broken record.
+func StrictAction() Option {
+	return func(o *options) { o.strictAction = true }
+}
+
+// RawAction separates integrity from semantic validation: an address
+// with an invalid action (-3) that passes the class E and CRC-8 checks
+// decodes into the Result its other fields represent, with a zero Delta
+// and Action holding the raw 3, returned alongside the -3 error as a
+// flag. A lookup selects the first address passing the integrity checks,
+// whatever its action. It's meant for forensic and debugging tools
+// handling the action themselves.
+func RawAction() Option {
+	return func(o *options) { o.rawAction = true }
+}
+
+// ReportInvalidAction makes a lookup append to addrs every address of
+// the response with an invalid action (-3), whether or not a valid one
+// was found. Such addresses are skipped silently otherwise; reporting
+// them helps detecting partially broken zones without failing the
+// lookup as StrictAction does.
+func ReportInvalidAction(addrs *[]string) Option {
+	return func(o *options) { o.invalidAction = addrs }
+}
+
+// CRC makes decoding verify integrity using v instead of the CRC-8 of
+// the specification, for experimenting with alternative schemes on
+// private zones.
+func CRC(v CRCVerifier) Option {
+	return func(o *options) { o.crc = v }
+}
+
+// Baseline makes decoding reject an announcement without a leap-second
+// (Delta 0) whose DTAI differs from dtai, the offset known to be in
+// effect, with the unexpected offset change (-5) error. The offset
+// can't change without a declared leap, so such a record is malformed
+// or spoofed, or the baseline is stale. Where SanityCheck compares two
+// announcements, Baseline checks a single one against known state.
+func Baseline(dtai int) Option {
+	return func(o *options) { o.baseline = &dtai }
+}
+
+// TTLBounds makes a lookup fail with the TTL out of bounds (-13) error
+// when the time-to-live of the record is less than min or more than max,
+// a max of zero meaning no upper bound. A suspiciously low or high TTL
+// can indicate a misconfigured or spoofed zone. The TTL is only checked
+// when the resolver implements ResolverTTL. The option is ignored by
+// Decode and Cache, which see addresses only; a Cache expires entries
+// by its own TTLs and not by the TTL of the record.
+func TTLBounds(min, max time.Duration) Option {
+	return func(o *options) { o.ttlBounds = &[2]time.Duration{min, max} }
+}
+
+// checkTTL checks ttl, as returned by r, against TTLBounds.
+func (o *options) checkTTL(r Resolver, ttl time.Duration) error {
+	if o.ttlBounds == nil {
+		return nil
+	}
+	if _, ok := r.(ResolverTTL); !ok {
+		return nil
+	}
+	min, max := o.ttlBounds[0], o.ttlBounds[1]
+	if ttl < min || (max > 0 && ttl > max) {
+		return &Error{Code: CodeTTLOutOfBounds, Err: fmt.Errorf("ttl %v out of bounds [%v,%v]", ttl, min, max)}
+	}
+	return nil
+}
+
+// RejectPast makes decoding reject an announcement whose announced month
+// ended before t, see Result.Time, with the stale announcement (-12)
+// error. A lookup skips such addresses like other invalid ones. It's
+// meant for scheduling, for which an announcement of the past is
+// useless: pass the current time.
+func RejectPast(t time.Time) Option {
+	return func(o *options) { o.rejectPast = &t }
+}
+
+// SignedDTAI makes decoding interpret the 7 bit dTAI field as two's
+// complement, yielding a DTAI in [-64,63] instead of [0,127], as the
+// specification allows it to be redefined. Encode such a Result using
+// EncodeSigned. See DecodeBoth for both interpretations at once.
+func SignedDTAI() Option {
+	return func(o *options) { o.signedDTAI = true }
+}
@@ -0,0 +1,304 @@
+package dnsleapsecs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTentative(t *testing.T) {
+	// 244.23.35.255 (Bulletin C 49) with a corrupted CRC octet
+	const ip = "244.23.35.254"
+	want := Result{2015, 6, 35, +1, 2}
+
+	r, err := Decode(ip)
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -2 {
+		t.Fatalf("got %#v, want code -2", err)
+	}
+	if r != (Result{}) {
+		t.Errorf("got result: %#v", r)
+	}
+
+	r, err = Decode(ip, Tentative())
+	if !errors.As(err, &e) || e.Code != -2 {
+		t.Fatalf("got %#v, want code -2", err)
+	}
+	if r != want {
+		t.Errorf("got %#v, want: %#v", r, want)
+	}
+
+	tr := testResolver{addr: ip}
+	gotIP, r, err := Lookup(context.Background(), tr, Tentative())
+	if !errors.As(err, &e) || e.Code != -2 {
+		t.Fatalf("got %#v, want code -2", err)
+	}
+	if gotIP != ip {
+		t.Errorf("got %q, want: %q", gotIP, ip)
+	}
+	if r != want {
+		t.Errorf("got %#v, want: %#v", r, want)
+	}
+
+	// An invalid action keeps its raw value.
+	r, _ = Decode("241.179.152.72", Tentative()) // corrupted CRC octet
+	if r.Delta != 0 || r.Action != 3 {
+		t.Errorf("got %#v, want: delta 0, action 3", r)
+	}
+
+	// A valid record is unaffected.
+	r, err = Decode("244.23.35.255", Tentative())
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if r != want {
+		t.Errorf("got %#v, want: %#v", r, want)
+	}
+}
+
+func TestNoAnnouncement(t *testing.T) {
+	ctx := context.Background()
+	_, _, err := Lookup(ctx, testResolver{}, NoAnnouncement())
+	if !errors.Is(err, ErrNoAnnouncement) {
+		t.Errorf("got %#v, want: %#v", err, ErrNoAnnouncement)
+	}
+
+	_, _, err = Lookup(ctx, testResolver{})
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -11 {
+		t.Errorf("got %#v, want code -11", err)
+	}
+
+	tr := testResolver{err: errors.New("some lookup error")}
+	_, _, err = Lookup(ctx, tr, NoAnnouncement())
+	if errors.Is(err, ErrNoAnnouncement) || !errors.As(err, &e) || e.Code != -10 {
+		t.Errorf("got %#v, want code -10", err)
+	}
+}
+
+func TestStrictAction(t *testing.T) {
+	ctx := context.Background()
+	tr := testResolver{addrs: []string{
+		"255.209.76.40",  // invalid checksum
+		"241.179.152.73", // invalid action
+		"240.3.9.77",
+	}}
+
+	ip, r, err := Lookup(ctx, tr)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if ip != "240.3.9.77" || r != (Result{1971, 12, 9, +1, 2}) {
+		t.Errorf("got (%q, %#v)", ip, r)
+	}
+
+	ip, r, err = Lookup(ctx, tr, StrictAction())
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -3 {
+		t.Fatalf("got %#v, want code -3", err)
+	}
+	if ip != "241.179.152.73" {
+		t.Errorf("got %q, want: %q", ip, "241.179.152.73")
+	}
+	if r != (Result{}) {
+		t.Errorf("got result: %#v", r)
+	}
+}
+
+// xorVerifier uses the XOR of the message octets as integrity octet.
+type xorVerifier struct{}
+
+func (xorVerifier) Verify(msg uint32) bool { return xorVerifier{}.Compute(msg&^0xff) == msg&0xff }
+
+func (xorVerifier) Compute(msg uint32) uint32 {
+	return (msg >> 24) ^ (msg >> 16 & 0xff) ^ (msg >> 8 & 0xff)
+}
+
+func TestRawAction(t *testing.T) {
+	ctx := context.Background()
+	want := Result{1989, 12, 24, 0, 3}
+
+	r, err := Decode("241.179.152.73", RawAction())
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -3 {
+		t.Fatalf("got %#v, want code -3", err)
+	}
+	if r != want {
+		t.Errorf("got %#v, want: %#v", r, want)
+	}
+
+	tr := testResolver{addrs: []string{
+		"255.209.76.40",  // invalid checksum
+		"241.179.152.73", // invalid action
+		"240.3.9.77",
+	}}
+	ip, r, err := Lookup(ctx, tr, RawAction())
+	if !errors.As(err, &e) || e.Code != -3 {
+		t.Fatalf("got %#v, want code -3", err)
+	}
+	if ip != "241.179.152.73" || r != want {
+		t.Errorf("got (%q, %#v), want: (%q, %#v)", ip, r, "241.179.152.73", want)
+	}
+
+	// A valid address is selected as usual.
+	ip, _, err = Lookup(ctx, testResolver{addrs: []string{"255.209.76.40", "240.3.9.77"}}, RawAction())
+	if err != nil || ip != "240.3.9.77" {
+		t.Errorf("got (%q, %v)", ip, err)
+	}
+}
+
+func TestReportInvalidAction(t *testing.T) {
+	ctx := context.Background()
+	tr := testResolver{addrs: []string{
+		"241.179.152.73", // invalid action
+		"255.209.76.40",  // invalid checksum
+		"240.3.9.77",
+		"241.179.152.73",
+	}}
+
+	var addrs []string
+	ip, r, err := Lookup(ctx, tr, ReportInvalidAction(&addrs))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if ip != "240.3.9.77" || r != (Result{1971, 12, 9, +1, 2}) {
+		t.Errorf("got (%q, %#v)", ip, r)
+	}
+	if len(addrs) != 1 || addrs[0] != "241.179.152.73" {
+		t.Errorf("got %q, want: [241.179.152.73]", addrs)
+	}
+
+	addrs = nil
+	if _, _, err := Lookup(ctx, testResolver{addr: "240.3.9.77"}, ReportInvalidAction(&addrs)); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if addrs != nil {
+		t.Errorf("got %q, want none", addrs)
+	}
+}
+
+func TestCRC(t *testing.T) {
+	// 244.23.35.255 with the XOR of 0x04, 0x17 and 0x23 as last octet
+	const ip = "244.23.35.48"
+	want := Result{2015, 6, 35, +1, 2}
+
+	_, err := Decode(ip)
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -2 {
+		t.Fatalf("got %#v, want code -2", err)
+	}
+
+	r, err := DecodeWith(StrictParser{}, ip, CRC(xorVerifier{}))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if r != want {
+		t.Errorf("got %#v, want: %#v", r, want)
+	}
+
+	_, err = Decode("244.23.35.255", CRC(xorVerifier{}))
+	if !errors.As(err, &e) || e.Code != -2 {
+		t.Errorf("got %#v, want code -2", err)
+	}
+
+	// The default verifier agrees with crc8.
+	for _, tv := range TestVectors {
+		u, _ := parseIPv4(tv.IP)
+		if got, want := (crc8Verifier{}).Verify(u&(1<<28-1)), crc8(u) == 0x80; got != want {
+			t.Errorf("%s: got %t, want: %t", tv.IP, got, want)
+		}
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	tests := []struct {
+		ip       string
+		baseline int
+		code     int
+	}{
+		{"242.18.28.160", 28, 0},  // 1993-12, dtai 28, no leap
+		{"242.18.28.160", 27, -5}, // changed without a leap
+		{"240.3.9.77", 8, 0},      // leaps aren't checked
+	}
+	for _, tt := range tests {
+		_, err := Decode(tt.ip, Baseline(tt.baseline))
+		var e *Error
+		switch {
+		case tt.code == 0 && err != nil:
+			t.Errorf("%s: got error: %v", tt.ip, err)
+		case tt.code != 0 && (!errors.As(err, &e) || e.Code != tt.code):
+			t.Errorf("%s: got %#v, want code %d", tt.ip, err, tt.code)
+		}
+	}
+
+	tr := testResolver{addrs: []string{"242.18.28.160", "240.3.9.77"}}
+	ip, _, err := Lookup(context.Background(), tr, Baseline(27))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if ip != "240.3.9.77" {
+		t.Errorf("got %q, want: %q", ip, "240.3.9.77")
+	}
+}
+
+func TestTTLBounds(t *testing.T) {
+	ctx := context.Background()
+	opt := TTLBounds(time.Minute, 24*time.Hour)
+	tr := func(ttl time.Duration) testResolverTTL {
+		return testResolverTTL{testResolver{addr: "244.23.35.255"}, ttl}
+	}
+
+	for _, ttl := range []time.Duration{time.Minute, time.Hour, 24 * time.Hour} {
+		if _, _, err := Lookup(ctx, tr(ttl), opt); err != nil {
+			t.Errorf("%v: got error: %v", ttl, err)
+		}
+	}
+	for _, ttl := range []time.Duration{0, time.Second, 25 * time.Hour} {
+		_, _, err := Lookup(ctx, tr(ttl), opt)
+		var e *Error
+		if !errors.As(err, &e) || e.Code != -13 {
+			t.Errorf("%v: got %#v, want code -13", ttl, err)
+		}
+		_, _, _, err = LookupHostChecked(ctx, tr(ttl), "leapsecond.utcd.org", opt)
+		if !errors.As(err, &e) || e.Code != -13 {
+			t.Errorf("%v: got %#v, want code -13", ttl, err)
+		}
+	}
+
+	if _, _, err := Lookup(ctx, tr(365*24*time.Hour), TTLBounds(time.Minute, 0)); err != nil {
+		t.Errorf("no upper bound: got error: %v", err)
+	}
+	// Without ResolverTTL the TTL is unknown and isn't checked.
+	if _, _, err := Lookup(ctx, testResolver{addr: "244.23.35.255"}, opt); err != nil {
+		t.Errorf("no ttl: got error: %v", err)
+	}
+}
+
+func TestRejectPast(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, _, err := Lookup(ctx, testResolver{addr: "242.18.28.160"}, RejectPast(time.Now())) // 1993-12
+	if !errors.Is(err, ErrStaleAnnouncement) {
+		t.Fatalf("got %#v, want code -12", err)
+	}
+
+	tr := testResolver{addrs: []string{"242.18.28.160", "244.23.35.255"}}
+	ip, r, err := Lookup(ctx, tr, RejectPast(now))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if ip != "244.23.35.255" || r != (Result{2015, 6, 35, +1, 2}) {
+		t.Errorf("got (%q, %#v)", ip, r)
+	}
+
+	// The announced month ends with its last second, the leap-second.
+	leap := time.Date(2015, 6, 30, 23, 59, 60, 0, time.UTC)
+	if _, err := Decode("244.23.35.255", RejectPast(leap)); err != nil {
+		t.Errorf("got error: %v", err)
+	}
+	if _, err := Decode("244.23.35.255", RejectPast(leap.Add(time.Second))); !errors.Is(err, ErrStaleAnnouncement) {
+		t.Errorf("got %#v, want code -12", err)
+	}
+}
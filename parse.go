@@ -0,0 +1,87 @@
+package dnsleapsecs
+
+import (
+	"errors"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// AddressParser parses a textual address into a 32 bit integer in
+// network order, the first octet being the most significant byte.
+type AddressParser interface {
+	Parse(s string) (uint32, error)
+}
+
+// StrictParser is the AddressParser used by Decode. It accepts numeric
+// IPv4 strings ("244.23.35.255") only, of which every octet must be a
+// decimal number in [0,255] without superfluous leading zeros.
+// Surrounding whitespace is ignored.
+type StrictParser struct{}
+
+// Parse implements AddressParser.
+func (StrictParser) Parse(s string) (uint32, error) { return parseIPv4(s) }
+
+// ReversedParser is an AddressParser for numeric IPv4 strings with the
+// octets in reverse order, least significant first, as some capture
+// tools present addresses read little-endian. It's otherwise as strict
+// as StrictParser.
+type ReversedParser struct{}
+
+// Parse implements AddressParser.
+func (ReversedParser) Parse(s string) (uint32, error) {
+	u, err := parseIPv4(s)
+	return bits.ReverseBytes32(u), err
+}
+
+// DecodeReversed is like Decode, but for an address with its octets in
+// reverse order ("255.35.23.244" for 244.23.35.255). Use it only for
+// addresses known to be byte swapped by tooling: a correct address
+// reversed rarely decodes, as it fails the class E check unless its last
+// octet starts with 0xf, and then almost surely the CRC-8 check.
+func DecodeReversed(ip string, opts ...Option) (Result, error) {
+	return DecodeWith(ReversedParser{}, ip, opts...)
+}
+
+// DecodeWith is like Decode, but uses p to parse ip. An error returned
+// by p is reported as a malformed address (-6) wrapping it.
+func DecodeWith(p AddressParser, ip string, opts ...Option) (Result, error) {
+	u, err := p.Parse(ip)
+	if err != nil {
+		return Result{}, &Error{Code: CodeMalformedAddress, IP: ip, Err: err}
+	}
+	r, err := decodeUint32(u, newOptions(opts))
+	return r, annotate(err, ip, "")
+}
+
+// parseIPv4 strictly parses a numeric IPv4 string ("244.23.35.255") into
+// a network-order 32 bit integer. Surrounding whitespace, such as a
+// trailing newline, is ignored. Each of the four octets must be a
+// decimal number in [0,255] without superfluous leading zeros, which
+// are rejected rather than normalized as some parsers read them octal.
+func parseIPv4(s string) (uint32, error) {
+	octets := strings.Split(strings.TrimSpace(s), ".")
+	if len(octets) != 4 {
+		return 0, errors.New("want 4 octets, got " + strconv.Itoa(len(octets)))
+	}
+	var u uint32
+	for _, o := range octets {
+		if o == "" {
+			return 0, errors.New("empty octet")
+		}
+		if len(o) > 1 && o[0] == '0' {
+			return 0, errors.New("octet " + strconv.Quote(o) + " has leading zero")
+		}
+		for i := 0; i < len(o); i++ {
+			if o[i] < '0' || o[i] > '9' {
+				return 0, errors.New("octet " + strconv.Quote(o) + " is not a number")
+			}
+		}
+		n, err := strconv.ParseUint(o, 10, 8)
+		if err != nil {
+			return 0, errors.New("octet " + strconv.Quote(o) + " out of range")
+		}
+		u = u<<8 | uint32(n)
+	}
+	return u, nil
+}
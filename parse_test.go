@@ -0,0 +1,142 @@
+package dnsleapsecs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseIPv4(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint32
+	}{
+		{"240.3.9.77", 0xf003094d},
+		{"0.0.0.0", 0},
+		{"255.255.255.255", 0xffffffff},
+		{" 240.3.9.77", 0xf003094d},
+		{"240.3.9.77\n", 0xf003094d},
+		{"\t240.3.9.77 \r\n", 0xf003094d},
+	}
+	for _, tt := range tests {
+		got, err := parseIPv4(tt.in)
+		if err != nil {
+			t.Errorf("%q: got error: %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("%q: got 0x%x, want: 0x%x", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{
+		"",
+		"240.3.9",
+		"240.3.9.77.1",
+		"240..9.77",
+		"240.03.9.77",
+		"240.3.9.077",
+		"00.3.9.77",
+		"256.3.9.77",
+		"240.3.9.-1",
+		"240.3.9.+7",
+		"240.3.9.77x",
+		"0x0f.3.9.77",
+		"256.0.0.0",
+		"240.3.9.256",
+		"240. 3.9.77",
+		"240.3 .9.77",
+		" 240.003.009.077",
+	} {
+		if _, err := parseIPv4(in); err == nil {
+			t.Errorf("%q: got no error", in)
+		}
+	}
+}
+
+func TestDecodeWhitespace(t *testing.T) {
+	r, err := Decode("244.23.35.255\n")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if r != (Result{2015, 6, 35, +1, 2}) {
+		t.Errorf("got %#v", r)
+	}
+	for _, ip := range []string{"256.0.0.0", "244.23. 35.255"} {
+		if _, err := Decode(ip); !errors.Is(err, ErrMalformedAddress) {
+			t.Errorf("%q: got %#v, want code -6", ip, err)
+		}
+	}
+}
+
+func TestDecodeLeadingZero(t *testing.T) {
+	for _, ip := range []string{"240.03.9.77", "240.03.09.77"} {
+		r, err := Decode(ip)
+		var e *Error
+		if !errors.As(err, &e) || e.Code != -6 {
+			t.Errorf("%q: got %#v, want code -6", ip, err)
+		}
+		if r != (Result{}) {
+			t.Errorf("%q: got result: %#v", ip, r)
+		}
+	}
+}
+
+// hexParser parses addresses written as a single hexadecimal number.
+type hexParser struct{}
+
+func (hexParser) Parse(s string) (uint32, error) {
+	u, err := strconv.ParseUint(s, 16, 32)
+	return uint32(u), err
+}
+
+func TestDecodeWith(t *testing.T) {
+	for _, tv := range TestVectors {
+		t.Run(tv.IP, func(t *testing.T) {
+			r, err := DecodeWith(StrictParser{}, tv.IP)
+			var e *Error
+			if errors.As(err, &e) && *e != (Error{Code: tv.Err.Code, IP: tv.IP}) {
+				t.Errorf("got %#v, want: %#v", err, tv.Err)
+			}
+			if r != tv.Result {
+				t.Errorf("got %#v, want: %#v", r, tv.Result)
+			}
+		})
+	}
+
+	t.Run("hex", func(t *testing.T) {
+		r, err := DecodeWith(hexParser{}, "f003094d")
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if want := (Result{1971, 12, 9, +1, 2}); r != want {
+			t.Errorf("got %#v, want: %#v", r, want)
+		}
+
+		_, err = DecodeWith(hexParser{}, "240.3.9.77")
+		var e *Error
+		if !errors.As(err, &e) || e.Code != -6 || e.Err == nil {
+			t.Errorf("got %#v, want code -6 wrapping parse error", err)
+		}
+	})
+}
+
+func TestDecodeReversed(t *testing.T) {
+	for _, tv := range TestVectors {
+		octets := strings.Split(tv.IP, ".")
+		reversed := strings.Join([]string{octets[3], octets[2], octets[1], octets[0]}, ".")
+		want, wantErr := Decode(tv.IP)
+		r, err := DecodeReversed(reversed)
+		if r != want {
+			t.Errorf("%s: got %#v, want: %#v", reversed, r, want)
+		}
+		var e, we *Error
+		if errors.As(wantErr, &we) != errors.As(err, &e) || (e != nil && e.Code != we.Code) {
+			t.Errorf("%s: got %#v, want: %#v", reversed, err, wantErr)
+		}
+	}
+
+	if _, err := DecodeReversed("77.9.3.240.1"); err == nil {
+		t.Error("got no error")
+	}
+}
@@ -0,0 +1,131 @@
+package dnsleapsecs
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// NewRoundRobinResolver returns a Resolver querying the DNS servers
+// ("host" or "host:port") in turn on successive lookups, using the pure
+// Go resolver. When a server fails, the lookup moves on to the next one
+// until every server has been tried. It is safe for concurrent use.
+func NewRoundRobinResolver(servers []string) Resolver {
+	if len(servers) == 0 {
+		panic("no servers")
+	}
+	rr := &roundRobinResolver{resolvers: make([]*net.Resolver, len(servers))}
+	for i, server := range servers {
+		rr.resolvers[i] = NewGoResolver(server)
+	}
+	return rr
+}
+
+type roundRobinResolver struct {
+	resolvers []*net.Resolver
+	next      uint32
+}
+
+func (rr *roundRobinResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	n := uint32(len(rr.resolvers))
+	start := atomic.AddUint32(&rr.next, 1) - 1
+	var err error
+	for i := uint32(0); i < n; i++ {
+		var addrs []string
+		addrs, err = rr.resolvers[(start+i)%n].LookupHost(ctx, host)
+		if err == nil {
+			return addrs, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// NewGoResolver returns a pure Go resolver, bypassing cgo and the
+// system configuration, querying only the DNS server serverAddr ("host"
+// or "host:port", the port defaulting to 53). Dialing honors the context
+// of the lookup. The *net.Resolver satisfies Resolver, so Lookup can be
+// pointed at an arbitrary server.
+func NewGoResolver(serverAddr string) *net.Resolver {
+	server := serverAddr
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// NewRetryResolver returns a Resolver retrying failed lookups of r, for
+// up to attempts lookups in total, waiting backoff before the first retry
+// and doubling it for every next one. A lookup isn't retried when the
+// host doesn't exist, and retrying stops as soon as the context is done.
+// As only lookups are retried, decode errors never are. The Resolver
+// implements ResolverTTL if r does.
+func NewRetryResolver(r Resolver, attempts int, backoff time.Duration) Resolver {
+	if attempts < 1 {
+		panic("attempts less than 1")
+	}
+	rr := &retryResolver{r: r, attempts: attempts, backoff: backoff}
+	if _, ok := r.(ResolverTTL); ok {
+		return retryResolverTTL{rr}
+	}
+	return rr
+}
+
+type retryResolver struct {
+	r        Resolver
+	attempts int
+	backoff  time.Duration
+}
+
+func (rr *retryResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	var addrs []string
+	err := rr.retry(ctx, func() (err error) {
+		addrs, err = rr.r.LookupHost(ctx, host)
+		return err
+	})
+	return addrs, err
+}
+
+func (rr *retryResolver) retry(ctx context.Context, lookup func() error) error {
+	backoff := rr.backoff
+	for i := 1; ; i++ {
+		err := lookup()
+		if err == nil || i == rr.attempts || ctx.Err() != nil {
+			return err
+		}
+		var de *net.DNSError
+		if errors.As(err, &de) && de.IsNotFound {
+			return err
+		}
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		backoff *= 2
+	}
+}
+
+type retryResolverTTL struct{ *retryResolver }
+
+func (rr retryResolverTTL) LookupHostTTL(ctx context.Context, host string) ([]string, time.Duration, error) {
+	var addrs []string
+	var ttl time.Duration
+	err := rr.retry(ctx, func() (err error) {
+		addrs, ttl, err = rr.r.(ResolverTTL).LookupHostTTL(ctx, host)
+		return err
+	})
+	return addrs, ttl, err
+}
@@ -0,0 +1,192 @@
+package dnsleapsecs_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dwlnetnl/dnsleapsecs"
+	"github.com/dwlnetnl/dnsleapsecs/dnsleapsecstest"
+)
+
+// closedAddr returns the address of a UDP port nothing listens on.
+func closedAddr(t *testing.T) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := conn.LocalAddr().String()
+	conn.Close()
+	return addr
+}
+
+func TestRoundRobinResolver(t *testing.T) {
+	s1 := dnsleapsecstest.NewServer([]string{"240.3.9.77"}, time.Hour)
+	defer s1.Close()
+	s2 := dnsleapsecstest.NewServer([]string{"244.23.35.255"}, time.Hour)
+	defer s2.Close()
+
+	ctx := context.Background()
+	r := dnsleapsecs.NewRoundRobinResolver([]string{s1.Addr, closedAddr(t), s2.Addr})
+	dnsleapsecstest.AssertRespectsContext(t, r)
+
+	// The context check above took the first turn.
+	want := []string{
+		"244.23.35.255", // closed server fails over to s2
+		"244.23.35.255",
+		"240.3.9.77",
+		"244.23.35.255",
+	}
+	for i, w := range want {
+		ip, _, err := dnsleapsecs.LookupHost(ctx, r, "leapsecond.utcd.org.")
+		if err != nil {
+			t.Fatalf("%d: got error: %v", i, err)
+		}
+		if ip != w {
+			t.Errorf("%d: got %q, want: %q", i, ip, w)
+		}
+	}
+}
+
+func TestNewGoResolver(t *testing.T) {
+	s := dnsleapsecstest.NewServer([]string{"244.23.35.255"}, time.Hour)
+	defer s.Close()
+
+	var r dnsleapsecs.Resolver = dnsleapsecs.NewGoResolver(s.Addr)
+	dnsleapsecstest.AssertRespectsContext(t, r)
+	ip, dr, err := dnsleapsecs.LookupHost(context.Background(), r, "leapsecond.utcd.org.")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	want := dnsleapsecs.Result{Year: 2015, Month: 6, DTAI: 35, Delta: +1, Action: 2}
+	if ip != "244.23.35.255" || dr != want {
+		t.Errorf("got (%q, %#v), want: (%q, %#v)", ip, dr, "244.23.35.255", want)
+	}
+}
+
+// flakyResolver fails the first fails lookups, counting every lookup.
+type flakyResolver struct {
+	fails    int
+	attempts int
+	err      error
+}
+
+func (fr *flakyResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	fr.attempts++
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if fr.attempts <= fr.fails {
+		return nil, fr.err
+	}
+	return []string{"244.23.35.255"}, nil
+}
+
+func TestRetryResolver(t *testing.T) {
+	ctx := context.Background()
+	servfail := &net.DNSError{Err: "server misbehaving", Name: "leapsecond.utcd.org", IsTemporary: true}
+
+	fr := &flakyResolver{fails: 2, err: servfail}
+	r := dnsleapsecs.NewRetryResolver(fr, 3, time.Millisecond)
+	_, dr, err := dnsleapsecs.LookupHost(ctx, r, "leapsecond.utcd.org")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if want := (dnsleapsecs.Result{Year: 2015, Month: 6, DTAI: 35, Delta: +1, Action: 2}); dr != want {
+		t.Errorf("got %#v, want: %#v", dr, want)
+	}
+	if fr.attempts != 3 {
+		t.Errorf("got %d attempts, want: 3", fr.attempts)
+	}
+
+	fr = &flakyResolver{fails: 3, err: servfail}
+	_, _, err = dnsleapsecs.LookupHost(ctx, dnsleapsecs.NewRetryResolver(fr, 3, time.Millisecond), "leapsecond.utcd.org")
+	if !errors.Is(err, dnsleapsecs.ErrLookupFailed) || fr.attempts != 3 {
+		t.Errorf("got (%v, %d attempts), want code -10 after 3", err, fr.attempts)
+	}
+
+	fr = &flakyResolver{fails: 1, err: &net.DNSError{Err: "no such host", IsNotFound: true}}
+	dnsleapsecs.LookupHost(ctx, dnsleapsecs.NewRetryResolver(fr, 3, time.Millisecond), "leapsecond.utcd.org")
+	if fr.attempts != 1 {
+		t.Errorf("not found: got %d attempts, want: 1", fr.attempts)
+	}
+
+	// Decode errors aren't retried.
+	sr := dnsleapsecstest.StaticResolver{Addrs: []string{"255.209.76.40"}, TTL: time.Hour}
+	_, _, err = dnsleapsecs.LookupHost(ctx, dnsleapsecs.NewRetryResolver(sr, 3, time.Hour), "leapsecond.utcd.org")
+	if !errors.Is(err, dnsleapsecs.ErrInvalidChecksum) {
+		t.Errorf("got %v, want code -2", err)
+	}
+
+	// Retrying stops when the context is done.
+	fr = &flakyResolver{fails: 3, err: servfail}
+	r = dnsleapsecs.NewRetryResolver(fr, 3, time.Hour)
+	dnsleapsecstest.AssertRespectsContext(t, r)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := dnsleapsecs.LookupHost(ctx, r, "leapsecond.utcd.org"); err == nil {
+		t.Error("got no error")
+	}
+
+	if _, ok := dnsleapsecs.NewRetryResolver(sr, 1, 0).(dnsleapsecs.ResolverTTL); !ok {
+		t.Error("ResolverTTL not preserved")
+	}
+	if _, ok := dnsleapsecs.NewRetryResolver(fr, 1, 0).(dnsleapsecs.ResolverTTL); ok {
+		t.Error("ResolverTTL implemented")
+	}
+}
+
+func TestDoHResolver(t *testing.T) {
+	addrs := []net.IP{net.ParseIP("244.23.35.255").To4()}
+	var method string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		var query []byte
+		var err error
+		if r.Method == http.MethodPost {
+			query, err = io.ReadAll(r.Body)
+		} else {
+			query, err = base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+		}
+		resp := dnsleapsecstest.Answer(query, addrs, 3600)
+		if err != nil || resp == nil {
+			http.Error(w, "bad query", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(resp)
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	want := dnsleapsecs.Result{Year: 2015, Month: 6, DTAI: 35, Delta: +1, Action: 2}
+	for _, post := range []bool{false, true} {
+		r := &dnsleapsecs.DoHResolver{URL: ts.URL, Client: ts.Client(), Post: post}
+		dnsleapsecstest.AssertRespectsContext(t, r)
+		ip, dr, ttl, err := dnsleapsecs.LookupHostTTL(ctx, r, "leapsecond.utcd.org.")
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if ip != "244.23.35.255" || dr != want || ttl != time.Hour {
+			t.Errorf("got (%q, %#v, %v)", ip, dr, ttl)
+		}
+		if wantMethod := map[bool]string{false: http.MethodGet, true: http.MethodPost}[post]; method != wantMethod {
+			t.Errorf("got method %s, want: %s", method, wantMethod)
+		}
+	}
+
+	nf := httptest.NewServer(http.NotFoundHandler())
+	defer nf.Close()
+	r := &dnsleapsecs.DoHResolver{URL: nf.URL, Client: nf.Client()}
+	_, _, err := dnsleapsecs.LookupHost(ctx, r, "leapsecond.utcd.org.")
+	if !errors.Is(err, dnsleapsecs.ErrLookupFailed) {
+		t.Errorf("got %#v, want code -10", err)
+	}
+}
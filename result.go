@@ -0,0 +1,197 @@
+package dnsleapsecs
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+)
+
+// Proto returns r as int32 fields, ready to be copied into a generated
+// protobuf message. action is the raw 'd' code: 0 for no change,
+// 1 for subtract one and 2 for add one to dTAI.
+func (r Result) Proto() (year, month, dtai, action int32) {
+	return int32(r.Year), int32(r.Month), int32(r.DTAI), int32(actionCode(r.Delta))
+}
+
+// actionCode maps delta to the 'd' field value.
+func actionCode(delta int) uint32 {
+	switch delta {
+	case -1:
+		return 1
+	case +1:
+		return 2
+	}
+	return 0
+}
+
+// LeapDirection is the direction of an announced leap-second.
+type LeapDirection int
+
+// Leap directions.
+const (
+	NoLeap       LeapDirection = iota // dTAI stays the same
+	PositiveLeap                      // a second is inserted, dTAI increments
+	NegativeLeap                      // a second is removed, dTAI decrements
+)
+
+func (d LeapDirection) String() string {
+	switch d {
+	case NoLeap:
+		return "none"
+	case PositiveLeap:
+		return "positive"
+	case NegativeLeap:
+		return "negative"
+	}
+	return "LeapDirection(" + strconv.Itoa(int(d)) + ")"
+}
+
+// Direction returns the direction of the leap-second announced for the
+// end of the month.
+func (r Result) Direction() LeapDirection {
+	switch {
+	case r.Delta > 0:
+		return PositiveLeap
+	case r.Delta < 0:
+		return NegativeLeap
+	}
+	return NoLeap
+}
+
+// OffsetAfterHorizon returns the dTAI in effect from the first day of
+// the month following the announced month, which is DTAI+Delta.
+// DTAI itself is in effect until the announced month ends.
+func (r Result) OffsetAfterHorizon() int {
+	return r.DTAI + r.Delta
+}
+
+// OffsetForMonth returns the dTAI in effect during the given month as
+// far as r tells: DTAI for months up to and including the announced
+// month and DTAI+Delta for the month following it. What happens after
+// that isn't announced yet, false is returned for later months. The
+// announcement doesn't know about earlier leap-seconds either, for past
+// months the historical table is authoritative. month is normalized
+// like time.Date does.
+func (r Result) OffsetForMonth(year, month int) (int, bool) {
+	switch n := MonthsSince1971(year, month) - MonthsSince1971(r.Year, r.Month); {
+	case n <= 0:
+		return r.DTAI, true
+	case n == 1:
+		return r.DTAI + r.Delta, true
+	}
+	return 0, false
+}
+
+// WithDelta returns a copy of r with Delta set to d and Action to match.
+// The result is not validated, use Validate or Encode for that.
+func (r Result) WithDelta(d int) Result {
+	r.Delta = d
+	r.Action = int(actionCode(d))
+	return r
+}
+
+// WithDTAI returns a copy of r with DTAI set to n. The result is not
+// validated, use Validate or Encode for that.
+func (r Result) WithDTAI(n int) Result {
+	r.DTAI = n
+	return r
+}
+
+// Hash returns a compact key for r: Results that are Equal produce equal
+// hashes, so Action is ignored. For a valid Result it is its encoding as
+// returned by EncodeUint32, otherwise a FNV-1a hash of its fields, which
+// can't collide with the encoding of a valid Result as its top nibble is
+// never 0xf.
+func (r Result) Hash() uint32 {
+	r.Action = 0
+	if u, err := EncodeUint32(r); err == nil {
+		return u
+	}
+	h := fnv.New32a()
+	for _, v := range [...]int{r.Year, r.Month, r.DTAI, r.Delta} {
+		u := uint64(v)
+		h.Write([]byte{byte(u >> 56), byte(u >> 48), byte(u >> 40), byte(u >> 32),
+			byte(u >> 24), byte(u >> 16), byte(u >> 8), byte(u)})
+	}
+	return h.Sum32() &^ (1 << 28)
+}
+
+// Equal reports whether r and o announce the same: Year, Month, DTAI
+// and Delta are equal. Action isn't compared, as a zero Action stands
+// for the one of Delta.
+func (r Result) Equal(o Result) bool {
+	return r.Year == o.Year && r.Month == o.Month && r.DTAI == o.DTAI && r.Delta == o.Delta
+}
+
+// Before reports whether r orders before o: by horizon, then by DTAI and
+// then by Delta, which makes it a total order consistent with Equal.
+// Of announcements fetched from several mirrors, the one no other is
+// Before is the most recent.
+func (r Result) Before(o Result) bool {
+	switch {
+	case r.Year != o.Year:
+		return r.Year < o.Year
+	case r.Month != o.Month:
+		return r.Month < o.Month
+	case r.DTAI != o.DTAI:
+		return r.DTAI < o.DTAI
+	}
+	return r.Delta < o.Delta
+}
+
+// SanityCheck compares curr with an earlier fetched prev and reports
+// changes that a sequence of genuine announcements can't produce,
+// indicating data loss or spoofing.
+//
+// It assumes a leap-second can only occur at the end of a month and at
+// most once per month. The horizon must not move backwards and when it
+// stays the same, the announcement must not change. When it advances,
+// dTAI must differ from the offset prev announced for after its horizon
+// by no more than the number of month ends in between.
+func SanityCheck(prev, curr Result) error {
+	pm, cm := MonthsSince1971(prev.Year, prev.Month), MonthsSince1971(curr.Year, curr.Month)
+	switch {
+	case cm < pm:
+		return fmt.Errorf("horizon moved back from %04d-%02d to %04d-%02d",
+			prev.Year, prev.Month, curr.Year, curr.Month)
+	case cm == pm:
+		if !prev.Equal(curr) {
+			return fmt.Errorf("announcement for %04d-%02d changed", curr.Year, curr.Month)
+		}
+		return nil
+	}
+	diff := curr.DTAI - prev.OffsetAfterHorizon()
+	if diff < 0 {
+		diff = -diff
+	}
+	if n := cm - pm - 1; diff > n {
+		return fmt.Errorf("dtai changed by %d after %04d-%02d with %d month ends to %04d-%02d",
+			diff, prev.Year, prev.Month, n, curr.Year, curr.Month)
+	}
+	return nil
+}
+
+// SameOffsetAs reports whether r and o describe the same physical
+// offset, tolerating the transition from one announcement to the next.
+// For equal horizons DTAI and Delta must be equal. Otherwise the offset
+// the older announcement gives for after its horizon must equal the
+// DTAI of the newer one: an announcement of June with dTAI 35 and +1
+// matches one of December with dTAI 36, whatever its Delta.
+func (r Result) SameOffsetAs(o Result) bool {
+	rm, om := MonthsSince1971(r.Year, r.Month), MonthsSince1971(o.Year, o.Month)
+	switch {
+	case rm < om:
+		return r.OffsetAfterHorizon() == o.DTAI
+	case rm > om:
+		return o.OffsetAfterHorizon() == r.DTAI
+	}
+	return r.DTAI == o.DTAI && r.Delta == o.Delta
+}
+
+// ChangeFrom returns the net change in dTAI the announcement implies
+// relative to currentDTAI, the offset known to be in effect now, as
+// taken from the historical table or a previous fetch. That is the
+// offset in effect after the horizon minus currentDTAI.
+func (r Result) ChangeFrom(currentDTAI int) int {
+	return r.OffsetAfterHorizon() - currentDTAI
+}
@@ -0,0 +1,248 @@
+package dnsleapsecs
+
+import "testing"
+
+func TestResultProto(t *testing.T) {
+	tests := []struct {
+		r                         Result
+		year, month, dtai, action int32
+	}{
+		{Result{1971, 12, 9, +1, 2}, 1971, 12, 9, 2},
+		{Result{1993, 12, 28, 0, 0}, 1993, 12, 28, 0},
+		{Result{2135, 1, 72, -1, 1}, 2135, 1, 72, 1},
+	}
+	for _, tt := range tests {
+		year, month, dtai, action := tt.r.Proto()
+		if year != tt.year || month != tt.month || dtai != tt.dtai || action != tt.action {
+			t.Errorf("%#v: got (%d, %d, %d, %d), want: (%d, %d, %d, %d)", tt.r,
+				year, month, dtai, action, tt.year, tt.month, tt.dtai, tt.action)
+		}
+	}
+}
+
+func TestResultDirection(t *testing.T) {
+	tests := []struct {
+		r    Result
+		want LeapDirection
+		s    string
+	}{
+		{Result{1971, 12, 9, +1, 2}, PositiveLeap, "positive"},
+		{Result{1993, 12, 28, 0, 0}, NoLeap, "none"},
+		{Result{2135, 1, 72, -1, 1}, NegativeLeap, "negative"},
+	}
+	for _, tt := range tests {
+		got := tt.r.Direction()
+		if got != tt.want {
+			t.Errorf("%#v: got %v, want: %v", tt.r, got, tt.want)
+		}
+		if got.String() != tt.s {
+			t.Errorf("got %q, want: %q", got.String(), tt.s)
+		}
+	}
+	if got, want := LeapDirection(7).String(), "LeapDirection(7)"; got != want {
+		t.Errorf("got %q, want: %q", got, want)
+	}
+}
+
+func TestResultOffsetAfterHorizon(t *testing.T) {
+	for _, tv := range TestVectors {
+		if got, want := tv.Result.OffsetAfterHorizon(), tv.Result.DTAI+tv.Result.Delta; got != want {
+			t.Errorf("%s: got %d, want: %d", tv.IP, got, want)
+		}
+	}
+	if got := (Result{2015, 6, 35, +1, 2}).OffsetAfterHorizon(); got != 36 {
+		t.Errorf("got %d, want: 36", got)
+	}
+}
+
+func TestResultOffsetForMonth(t *testing.T) {
+	r := Result{2015, 6, 35, +1, 2}
+	tests := []struct {
+		year, month int
+		want        int
+		ok          bool
+	}{
+		{2015, 6, 35, true},
+		{2015, 1, 35, true},
+		{2015, 7, 36, true},
+		{2014, 19, 36, true}, // normalized to July 2015
+		{2015, 8, 0, false},
+		{2016, 1, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := r.OffsetForMonth(tt.year, tt.month)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%04d-%02d: got (%d, %t), want: (%d, %t)", tt.year, tt.month, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestResultWith(t *testing.T) {
+	r := Result{2015, 6, 35, +1, 2}
+	if got, want := r.WithDelta(-1), (Result{2015, 6, 35, -1, 1}); got != want {
+		t.Errorf("got %#v, want: %#v", got, want)
+	}
+	if got, want := r.WithDTAI(36), (Result{2015, 6, 36, +1, 2}); got != want {
+		t.Errorf("got %#v, want: %#v", got, want)
+	}
+	if want := (Result{2015, 6, 35, +1, 2}); r != want {
+		t.Errorf("receiver modified: %#v", r)
+	}
+}
+
+func TestResultHash(t *testing.T) {
+	if got, want := (Result{1971, 12, 9, +1, 2}).Hash(), uint32(0xf003094d); got != want {
+		t.Errorf("got 0x%x, want: 0x%x", got, want)
+	}
+
+	invalid := Result{2015, 6, 35, 2, 0}
+	h := invalid.Hash()
+	if h>>28 == 0xf {
+		t.Errorf("got 0x%x, colliding with valid encodings", h)
+	}
+	if h != invalid.Hash() {
+		t.Error("hash not stable")
+	}
+	if h == invalid.WithDelta(3).Hash() {
+		t.Errorf("got 0x%x for different results", h)
+	}
+
+	// Action isn't hashed, like it isn't compared by Equal.
+	for _, pair := range [][2]Result{
+		{{2015, 6, 200, +1, 0}, Result{2015, 6, 200, +1, 0}.WithDelta(+1)},
+		{{2015, 6, 35, +1, 0}, {2015, 6, 35, +1, 1}},
+	} {
+		if !pair[0].Equal(pair[1]) {
+			t.Fatalf("%#v and %#v not equal", pair[0], pair[1])
+		}
+		if h0, h1 := pair[0].Hash(), pair[1].Hash(); h0 != h1 {
+			t.Errorf("%#v and %#v: got 0x%x and 0x%x", pair[0], pair[1], h0, h1)
+		}
+	}
+
+	seen := make(map[uint32]Result)
+	for _, tv := range TestVectors {
+		if r, ok := seen[tv.Result.Hash()]; ok && r != tv.Result {
+			t.Errorf("%#v and %#v have equal hash", r, tv.Result)
+		}
+		seen[tv.Result.Hash()] = tv.Result
+	}
+}
+
+func TestResultEqualBefore(t *testing.T) {
+	var results []Result
+	for _, tv := range TestVectors {
+		if tv.Err == nil {
+			results = append(results, tv.Result)
+		}
+	}
+	results = append(results,
+		Result{2015, 6, 35, +1, 2},
+		Result{2015, 6, 35, 0, 0},
+		Result{2015, 6, 36, 0, 0},
+		Result{2015, 12, 36, 0, 0},
+	)
+	for i, a := range results {
+		for j, b := range results {
+			eq, lt, gt := a.Equal(b), a.Before(b), b.Before(a)
+			if i == j && (!eq || lt || gt) {
+				t.Errorf("%#v not equal to itself", a)
+			}
+			// Exactly one of equal, before and after holds.
+			if n := btoi(eq) + btoi(lt) + btoi(gt); n != 1 {
+				t.Errorf("%#v, %#v: got equal %t, before %t, after %t", a, b, eq, lt, gt)
+			}
+		}
+	}
+
+	if !(Result{2015, 6, 35, +1, 2}).Equal(Result{2015, 6, 35, +1, 0}) {
+		t.Error("action compared")
+	}
+	if !(Result{1993, 12, 28, 0, 0}).Before(Result{2015, 6, 35, +1, 2}) {
+		t.Error("got 1993-12 not before 2015-06")
+	}
+	if !(Result{2015, 6, 35, 0, 0}).Before(Result{2015, 6, 35, +1, 2}) {
+		t.Error("got delta 0 not before delta +1")
+	}
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestSanityCheck(t *testing.T) {
+	tests := []struct {
+		prev, curr Result
+		ok         bool
+	}{
+		{Result{2015, 6, 35, +1, 2}, Result{2015, 6, 35, +1, 2}, true},
+		{Result{2015, 6, 35, +1, 2}, Result{2015, 12, 36, 0, 0}, true},
+		{Result{2015, 12, 36, 0, 0}, Result{2016, 12, 36, +1, 2}, true},
+		{Result{2016, 12, 36, +1, 2}, Result{2017, 1, 37, 0, 0}, true},
+		{Result{2015, 12, 36, 0, 0}, Result{2016, 6, 37, 0, 0}, true}, // missed announcement
+		{Result{2015, 6, 35, +1, 2}, Result{2015, 6, 35, 0, 0}, false},
+		{Result{2015, 12, 36, 0, 0}, Result{2015, 6, 35, +1, 2}, false},
+		{Result{2016, 12, 36, +1, 2}, Result{2017, 1, 36, 0, 0}, false},
+		{Result{2016, 12, 36, +1, 2}, Result{2017, 2, 39, 0, 0}, false},
+		{Result{2015, 12, 36, 0, 0}, Result{2016, 6, 42, 0, 0}, false},
+	}
+	for _, tt := range tests {
+		err := SanityCheck(tt.prev, tt.curr)
+		if (err == nil) != tt.ok {
+			t.Errorf("%#v, %#v: got %v, want ok: %t", tt.prev, tt.curr, err, tt.ok)
+		}
+	}
+
+	// Action isn't compared, a zero Action stands for the one of Delta.
+	decoded, err := Decode("244.23.35.255")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SanityCheck(Result{2015, 6, 35, +1, 0}, decoded); err != nil {
+		t.Errorf("got %v", err)
+	}
+	if err := SanityCheck(decoded, Result{2015, 6, 35, +1, 0}); err != nil {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestResultSameOffsetAs(t *testing.T) {
+	tests := []struct {
+		r, o Result
+		want bool
+	}{
+		{Result{2015, 6, 35, +1, 2}, Result{2015, 6, 35, +1, 2}, true},
+		{Result{2015, 6, 35, +1, 2}, Result{2015, 6, 35, 0, 0}, false},
+		{Result{2015, 6, 35, +1, 2}, Result{2015, 12, 36, 0, 0}, true},
+		{Result{2015, 12, 36, 0, 0}, Result{2015, 6, 35, +1, 2}, true},
+		{Result{2015, 6, 35, +1, 2}, Result{2016, 12, 36, +1, 2}, true},
+		{Result{2015, 6, 35, +1, 2}, Result{2015, 12, 35, 0, 0}, false},
+		{Result{2015, 12, 36, 0, 0}, Result{2016, 6, 36, 0, 0}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.r.SameOffsetAs(tt.o); got != tt.want {
+			t.Errorf("%#v, %#v: got %t, want: %t", tt.r, tt.o, got, tt.want)
+		}
+	}
+}
+
+func TestResultChangeFrom(t *testing.T) {
+	tests := []struct {
+		r       Result
+		current int
+		want    int
+	}{
+		{Result{2015, 6, 35, +1, 2}, 35, 1},
+		{Result{2015, 6, 35, +1, 2}, 36, 0},
+		{Result{2015, 12, 36, 0, 0}, 35, 1},
+		{Result{2135, 1, 72, -1, 1}, 72, -1},
+	}
+	for _, tt := range tests {
+		if got := tt.r.ChangeFrom(tt.current); got != tt.want {
+			t.Errorf("%#v from %d: got %d, want: %d", tt.r, tt.current, got, tt.want)
+		}
+	}
+}
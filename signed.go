@@ -0,0 +1,38 @@
+package dnsleapsecs
+
+import "fmt"
+
+// DecodeBoth is like Decode, but returns both interpretations of the
+// dTAI field: as currently specified unsigned in [0,127], and signed in
+// [-64,63] as the specification allows it to be redefined. They differ
+// only when the top bit of the 7 bit field is set, as for DTAI 72.
+func DecodeBoth(ip string, opts ...Option) (unsigned, signed Result, err error) {
+	opt := newOptions(opts)
+	opt.signedDTAI = false
+	unsigned, err = decode(ip, opt)
+	if err != nil {
+		return unsigned, unsigned, err
+	}
+	signed = unsigned
+	signed.DTAI = signedDTAI(unsigned.DTAI)
+	return unsigned, signed, nil
+}
+
+// EncodeSigned is like Encode, but for a Result with a signed DTAI in
+// [-64,63], as decoded using the SignedDTAI option, which it encodes in
+// two's complement.
+func EncodeSigned(r Result) (string, error) {
+	if r.DTAI < -0x40 || r.DTAI > 0x3f {
+		return "", &Error{Code: CodeInvalidResult, Err: fmt.Errorf("signed dtai %d out of range [-64,63]", r.DTAI)}
+	}
+	r.DTAI &= 0x7f
+	return Encode(r)
+}
+
+// signedDTAI interprets the 7 bit dTAI field as two's complement.
+func signedDTAI(o int) int {
+	if o&0x40 != 0 {
+		return o - 0x80
+	}
+	return o
+}
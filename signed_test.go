@@ -0,0 +1,70 @@
+package dnsleapsecs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeBoth(t *testing.T) {
+	tests := []struct {
+		ip               string
+		unsigned, signed Result
+	}{
+		{"244.23.35.255", Result{2015, 6, 35, +1, 2}, Result{2015, 6, 35, +1, 2}},
+		{"255.76.200.237", Result{2135, 1, 72, -1, 1}, Result{2135, 1, -56, -1, 1}},
+	}
+	for _, tt := range tests {
+		u, s, err := DecodeBoth(tt.ip)
+		if err != nil {
+			t.Fatalf("%s: got error: %v", tt.ip, err)
+		}
+		if u != tt.unsigned {
+			t.Errorf("%s: got unsigned %#v, want: %#v", tt.ip, u, tt.unsigned)
+		}
+		if s != tt.signed {
+			t.Errorf("%s: got signed %#v, want: %#v", tt.ip, s, tt.signed)
+		}
+	}
+
+	_, _, err := DecodeBoth("255.209.76.40")
+	var e *Error
+	if !errors.As(err, &e) || e.Code != -2 {
+		t.Errorf("got %#v, want code -2", err)
+	}
+}
+
+func TestSignedDTAI(t *testing.T) {
+	const ip = "255.76.200.237"
+	r, err := Decode(ip)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if r.DTAI != 72 {
+		t.Errorf("got dtai %d, want: 72", r.DTAI)
+	}
+	s, err := Decode(ip, SignedDTAI())
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if want := (Result{2135, 1, -56, -1, 1}); s != want {
+		t.Errorf("got %#v, want: %#v", s, want)
+	}
+	if got, err := EncodeSigned(s); err != nil || got != ip {
+		t.Errorf("got (%q, %v), want: %q", got, err, ip)
+	}
+
+	// Below 64 both interpretations agree.
+	if s, _ := Decode("244.23.35.255", SignedDTAI()); s.DTAI != 35 {
+		t.Errorf("got dtai %d, want: 35", s.DTAI)
+	}
+	if u, _, _ := DecodeBoth(ip, SignedDTAI()); u.DTAI != 72 {
+		t.Errorf("got unsigned dtai %d, want: 72", u.DTAI)
+	}
+
+	for _, dtai := range []int{-65, 64} {
+		_, err := EncodeSigned(Result{2135, 1, dtai, 0, 0})
+		if !errors.Is(err, ErrInvalidResult) {
+			t.Errorf("%d: got %#v, want code -20", dtai, err)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package dnsleapsecs
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Announcement is a Result together with the values derived from it
+// at a given moment.
+type Announcement struct {
+	Result
+
+	// Offset is the dTAI in effect at that moment.
+	Offset int
+
+	// Horizon is the end of the announced month, which is the first
+	// instant of the month following it in UTC.
+	Horizon time.Time
+
+	// Leap is the instant Delta is applied to dTAI, which is the zero
+	// Time if no leap-second is announced.
+	Leap time.Time
+
+	// Pending reports whether the announced leap-second has yet to occur.
+	Pending bool
+}
+
+// Describe returns r together with the values derived from it at now.
+func (r Result) Describe(now time.Time) Announcement {
+	a := Announcement{
+		Result:  r,
+		Offset:  r.DTAI,
+		Horizon: r.horizon(),
+	}
+	if r.Delta != 0 {
+		a.Leap = a.Horizon
+		a.Pending = now.Before(a.Leap)
+	}
+	if !now.Before(a.Horizon) {
+		a.Offset += r.Delta
+	}
+	return a
+}
+
+// Window returns the announced month as the half-open interval
+// [start, end) in UTC during which DTAI applies. end is the first
+// instant of the following month, at which Delta has been applied:
+// an inserted leap-second precedes it, a removed one makes the month
+// end a second before it.
+func (r Result) Window() (start, end time.Time) {
+	return monthStart(r.Year, r.Month), r.horizon()
+}
+
+// Time returns the last second of the announced month in UTC, which is
+// when the announcement takes effect: 23:59:59 of the final day without
+// a leap-second. With Delta +1 it's the inserted 23:59:60, which a Go
+// Time normalizes to 00:00:00 of the following month; with Delta -1
+// the month ends a second early at 23:59:58.
+func (r Result) Time() time.Time {
+	return r.horizon().Add(time.Duration(r.Delta-1) * time.Second)
+}
+
+// horizon returns the end of the announced month.
+func (r Result) horizon() time.Time {
+	return monthStart(r.Year, r.Month+1)
+}
+
+// NextPublicationWindow returns the half-open interval [start, end) in
+// UTC in which the next IERS Bulletin C is expected to be published, or
+// the current one if now falls within it. It's a heuristic based on the
+// historical cadence of publishing in January and July: the window is
+// the whole month to allow for delays.
+func NextPublicationWindow(now time.Time) (start, end time.Time) {
+	now = now.UTC()
+	year, month := now.Year(), now.Month()
+	switch {
+	case month <= time.January:
+		start = monthStart(year, 1)
+	case month <= time.July:
+		start = monthStart(year, 7)
+	default:
+		start = monthStart(year+1, 1)
+	}
+	return start, start.AddDate(0, 1, 0)
+}
+
+// LeapJD returns the Julian Date of the instant the announced leap
+// second ends, which is the end of the announced month, and whether a
+// leap-second is announced at all. The date is on the UTC time scale,
+// so the end of June 2015 is JD 2457204.5.
+func (r Result) LeapJD() (float64, bool) {
+	if r.Delta == 0 {
+		return 0, false
+	}
+	const unixEpochJD = 2440587.5
+	return unixEpochJD + float64(r.horizon().Unix())/86400, true
+}
+
+// LeapIndicator returns the NTP leap indicator to advertise at now:
+// 1 when a second is to be inserted at the end of the current month,
+// 2 when one is to be deleted and 0 otherwise, including when the
+// announced month isn't the current UTC month. The indicator 3 for an
+// unsynchronized clock is never returned.
+//
+// Whether the announced month is the current one depends on the time,
+// which is passed as now rather than read from the clock, like Describe
+// and Apply do, so that callers and tests control it. Pass time.Now()
+// for the indicator to advertise right away.
+func (r Result) LeapIndicator(now time.Time) int {
+	start, end := r.Window()
+	if now.Before(start) || !now.Before(end) {
+		return 0
+	}
+	switch r.Direction() {
+	case PositiveLeap:
+		return 1
+	case NegativeLeap:
+		return 2
+	}
+	return 0
+}
+
+// Convention selects the sign of an offset between TAI and UTC.
+type Convention int
+
+// Sign conventions.
+const (
+	// TAIMinusUTC gives the offset as TAI-UTC, a positive dTAI as in
+	// UTC = TAI - dTAI.
+	TAIMinusUTC Convention = iota
+
+	// UTCMinusTAI gives the offset as UTC-TAI, a negative dTAI as in
+	// UTC - TAI = -dTAI.
+	UTCMinusTAI
+)
+
+// OffsetSeconds returns DTAI as int64 seconds for arithmetic with Unix
+// time, signed as TAI-UTC: positive for the current offset of 35 as in
+// UTC = TAI - 35 sec.
+func (r Result) OffsetSeconds() int64 {
+	return int64(r.DTAI)
+}
+
+// OffsetDuration returns DTAI as a time.Duration signed according to c.
+// An unknown Convention is taken as TAIMinusUTC, the zero value.
+func (r Result) OffsetDuration(c Convention) time.Duration {
+	d := time.Duration(r.DTAI) * time.Second
+	if c == UTCMinusTAI {
+		return -d
+	}
+	return d
+}
+
+// OffsetNTP returns DTAI, the TAI-UTC offset, in the 64 bit fixed point
+// format of NTP timestamps: the whole seconds in the upper 32 bits and
+// the fraction of a second in the lower 32 bits, which are always zero.
+// A negative DTAI is returned in two's complement, as in a signed NTP
+// time offset.
+func (r Result) OffsetNTP() uint64 {
+	return uint64(int64(r.DTAI)) << 32
+}
+
+// Apply returns the offset in effect at now given baselineDTAI, the
+// offset tracked so far, and a freshly fetched announcement r. Delta is
+// applied once the horizon has passed: from the first instant of the
+// month following the announced month, the instant 23:59:60 normalizes
+// to, the offset is DTAI+Delta; at any earlier instant the baseline is
+// returned unchanged. Delta is only applied to a baseline equal to
+// DTAI, so feeding the result back in as the baseline doesn't apply it
+// twice.
+func Apply(baselineDTAI int, r Result, now time.Time) int {
+	if now.Before(r.horizon()) || baselineDTAI != r.DTAI {
+		return baselineDTAI
+	}
+	return baselineDTAI + r.Delta
+}
+
+// ErrNoLeapSecond is returned by NextLeapSecond when the announcement
+// doesn't schedule a leap-second.
+var ErrNoLeapSecond = errors.New("no leap-second scheduled")
+
+// NextLeapSecond fetches the current announcement like Fetch does and
+// returns when the announced leap-second occurs, see Result.Time, and
+// its direction as Delta. ErrNoLeapSecond is returned when none is
+// scheduled up to the end of the announced month.
+func NextLeapSecond(ctx context.Context) (time.Time, int, error) {
+	_, r, err := Fetch(ctx)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	if r.Delta == 0 {
+		return time.Time{}, 0, ErrNoLeapSecond
+	}
+	return r.Time(), r.Delta, nil
+}
+
+// ConvertUTCtoTAI fetches the current announcement like Fetch does and
+// converts the UTC instant t to TAI with it. It is only accurate for
+// instants up to the end of the month following the announced month:
+// the announcement doesn't know about earlier or later leap-seconds.
+func ConvertUTCtoTAI(ctx context.Context, t time.Time) (time.Time, error) {
+	_, r, err := Fetch(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return r.TAIFromUTC(t), nil
+}
+
+// ConvertTAItoUTC is the counterpart of ConvertUTCtoTAI, converting the
+// TAI instant t to UTC. The same accuracy limits apply.
+func ConvertTAItoUTC(ctx context.Context, t time.Time) (time.Time, error) {
+	_, r, err := Fetch(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return r.UTCFromTAI(t), nil
+}
+
+// TAIFromUTC converts utc to TAI as in UTC = TAI - dTAI, applying DTAI
+// before the horizon, the end of the announced month, and DTAI+Delta
+// from it.
+func (r Result) TAIFromUTC(utc time.Time) time.Time {
+	if utc.Before(r.horizon()) {
+		return utc.Add(time.Duration(r.DTAI) * time.Second)
+	}
+	return utc.Add(time.Duration(r.OffsetAfterHorizon()) * time.Second)
+}
+
+// UTCFromTAI converts tai to UTC as in UTC = TAI - dTAI, applying
+// DTAI+Delta from the horizon expressed in TAI and DTAI before it.
+// An inserted leap-second maps to the first second of the following
+// month, as Go normalizes 23:59:60.
+func (r Result) UTCFromTAI(tai time.Time) time.Time {
+	after := time.Duration(r.OffsetAfterHorizon()) * time.Second
+	if !tai.Before(r.horizon().Add(after)) {
+		return tai.Add(-after)
+	}
+	return tai.Add(-time.Duration(r.DTAI) * time.Second)
+}
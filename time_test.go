@@ -0,0 +1,318 @@
+package dnsleapsecs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResultDescribe(t *testing.T) {
+	r := Result{2015, 6, 35, +1, 2}
+	leap := time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		now  time.Time
+		want Announcement
+	}{
+		{leap.Add(-time.Second), Announcement{r, 35, leap, leap, true}},
+		{leap, Announcement{r, 36, leap, leap, false}},
+	}
+	for _, tt := range tests {
+		got := r.Describe(tt.now)
+		if got != tt.want {
+			t.Errorf("%v: got %#v, want: %#v", tt.now, got, tt.want)
+		}
+	}
+
+	r = Result{1993, 12, 28, 0, 0}
+	horizon := time.Date(1994, 1, 1, 0, 0, 0, 0, time.UTC)
+	got := r.Describe(horizon)
+	if want := (Announcement{r, 28, horizon, time.Time{}, false}); got != want {
+		t.Errorf("got %#v, want: %#v", got, want)
+	}
+}
+
+func TestResultWindow(t *testing.T) {
+	tests := []struct {
+		r          Result
+		start, end time.Time
+	}{
+		{
+			Result{2015, 6, 35, +1, 2},
+			time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Result{1993, 12, 28, 0, 0},
+			time.Date(1993, 12, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(1994, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		start, end := tt.r.Window()
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("%#v: got [%v, %v), want: [%v, %v)", tt.r, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestResultTime(t *testing.T) {
+	tests := []struct {
+		r    Result
+		want time.Time
+	}{
+		{Result{2015, 6, 35, +1, 2}, time.Date(2015, 6, 30, 23, 59, 60, 0, time.UTC)},
+		{Result{2016, 12, 36, +1, 2}, time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Result{1993, 12, 28, 0, 0}, time.Date(1993, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{Result{2135, 1, 72, -1, 1}, time.Date(2135, 1, 31, 23, 59, 58, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got := tt.r.Time()
+		if !got.Equal(tt.want) || got.Location() != time.UTC {
+			t.Errorf("%#v: got %v, want: %v", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestNextPublicationWindow(t *testing.T) {
+	date := func(year, month, day int) time.Time {
+		return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		now        time.Time
+		start, end time.Time
+	}{
+		{date(2015, 1, 5), date(2015, 1, 1), date(2015, 2, 1)},
+		{date(2015, 2, 1), date(2015, 7, 1), date(2015, 8, 1)},
+		{date(2015, 7, 31), date(2015, 7, 1), date(2015, 8, 1)},
+		{date(2015, 8, 1), date(2016, 1, 1), date(2016, 2, 1)},
+		{date(2015, 12, 31), date(2016, 1, 1), date(2016, 2, 1)},
+	}
+	for _, tt := range tests {
+		start, end := NextPublicationWindow(tt.now)
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("%v: got [%v, %v), want: [%v, %v)", tt.now, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestResultLeapJD(t *testing.T) {
+	tests := []struct {
+		r    Result
+		jd   float64
+		leap bool
+	}{
+		{Result{2015, 6, 35, +1, 2}, 2457204.5, true},
+		{Result{2016, 12, 36, +1, 2}, 2457754.5, true},
+		{Result{1993, 12, 28, 0, 0}, 0, false},
+	}
+	for _, tt := range tests {
+		jd, leap := tt.r.LeapJD()
+		if jd != tt.jd || leap != tt.leap {
+			t.Errorf("%#v: got (%v, %t), want: (%v, %t)", tt.r, jd, leap, tt.jd, tt.leap)
+		}
+	}
+}
+
+func TestResultLeapIndicator(t *testing.T) {
+	june := time.Date(2015, 6, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		r    Result
+		now  time.Time
+		want int
+	}{
+		{Result{2015, 6, 35, +1, 2}, june, 1},
+		{Result{2015, 6, 35, -1, 1}, june, 2},
+		{Result{2015, 6, 35, 0, 0}, june, 0},
+		{Result{2015, 6, 35, +1, 2}, june.AddDate(0, -1, 0), 0},
+		{Result{2015, 6, 35, +1, 2}, time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC), 0},
+		{Result{2015, 6, 35, +1, 2}, time.Date(2015, 6, 30, 23, 59, 59, 0, time.UTC), 1},
+	}
+	for _, tt := range tests {
+		if got := tt.r.LeapIndicator(tt.now); got != tt.want {
+			t.Errorf("%#v at %v: got %d, want: %d", tt.r, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestResultOffsetSeconds(t *testing.T) {
+	r := Result{2015, 6, 35, +1, 2}
+	if got := r.OffsetSeconds(); got != 35 {
+		t.Errorf("got %d, want: 35", got)
+	}
+	// UTC = TAI - dTAI
+	tai := time.Date(2015, 6, 1, 0, 0, 35, 0, time.UTC)
+	if got, want := tai.Unix()-r.OffsetSeconds(), time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC).Unix(); got != want {
+		t.Errorf("got %d, want: %d", got, want)
+	}
+}
+
+func TestResultOffsetDuration(t *testing.T) {
+	r := Result{2015, 6, 35, +1, 2}
+	if got, want := r.OffsetDuration(TAIMinusUTC), 35*time.Second; got != want {
+		t.Errorf("got %v, want: %v", got, want)
+	}
+	if got, want := r.OffsetDuration(UTCMinusTAI), -35*time.Second; got != want {
+		t.Errorf("got %v, want: %v", got, want)
+	}
+	if got, want := r.OffsetDuration(Convention(7)), 35*time.Second; got != want {
+		t.Errorf("unknown convention: got %v, want: %v", got, want)
+	}
+
+	// UTC = TAI - dTAI
+	tai := time.Date(2015, 6, 1, 0, 0, 35, 0, time.UTC)
+	utc := time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)
+	if got := tai.Add(r.OffsetDuration(UTCMinusTAI)); !got.Equal(utc) {
+		t.Errorf("got %v, want: %v", got, utc)
+	}
+	if got := tai.Sub(utc); got != r.OffsetDuration(TAIMinusUTC) {
+		t.Errorf("got %v, want: %v", got, r.OffsetDuration(TAIMinusUTC))
+	}
+}
+
+func TestResultOffsetNTP(t *testing.T) {
+	tests := []struct {
+		dtai int
+		want uint64
+	}{
+		{35, 0x00000023_00000000},
+		{0, 0},
+		{127, 0x0000007f_00000000},
+		{-1, 0xffffffff_00000000},
+	}
+	for _, tt := range tests {
+		if got := (Result{DTAI: tt.dtai}).OffsetNTP(); got != tt.want {
+			t.Errorf("%d: got 0x%016x, want: 0x%016x", tt.dtai, got, tt.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	r := Result{2015, 6, 35, +1, 2}
+	leap := time.Date(2015, 6, 30, 23, 59, 60, 0, time.UTC)
+	tests := []struct {
+		baseline int
+		now      time.Time
+		want     int
+	}{
+		{35, leap.Add(-time.Nanosecond), 35},
+		{35, leap, 36},
+		{35, leap.Add(time.Hour), 36},
+		{36, leap.Add(time.Hour), 36}, // already applied
+		{34, leap.Add(-time.Hour), 34},
+	}
+	for _, tt := range tests {
+		if got := Apply(tt.baseline, r, tt.now); got != tt.want {
+			t.Errorf("%d at %v: got %d, want: %d", tt.baseline, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestConvertUTCtoTAI(t *testing.T) {
+	defer func(r Resolver) { defaultResolver = r }(defaultResolver)
+	defaultResolver = testResolver{addr: "244.23.35.255"}
+	ctx := context.Background()
+
+	tests := []struct {
+		utc, tai time.Time
+	}{
+		{
+			time.Date(2015, 6, 30, 23, 59, 59, 0, time.UTC),
+			time.Date(2015, 7, 1, 0, 0, 34, 0, time.UTC),
+		},
+		{
+			time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2015, 7, 1, 0, 0, 36, 0, time.UTC),
+		},
+		{
+			time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2015, 6, 1, 0, 0, 35, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		tai, err := ConvertUTCtoTAI(ctx, tt.utc)
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if !tai.Equal(tt.tai) {
+			t.Errorf("%v: got %v, want: %v", tt.utc, tai, tt.tai)
+		}
+		utc, err := ConvertTAItoUTC(ctx, tt.tai)
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if !utc.Equal(tt.utc) {
+			t.Errorf("%v: got %v, want: %v", tt.tai, utc, tt.utc)
+		}
+	}
+
+	// The inserted leap-second, TAI 00:00:35, is 23:59:60 in UTC.
+	utc, _ := ConvertTAItoUTC(ctx, time.Date(2015, 7, 1, 0, 0, 35, 0, time.UTC))
+	if want := time.Date(2015, 6, 30, 23, 59, 60, 0, time.UTC); !utc.Equal(want) {
+		t.Errorf("got %v, want: %v", utc, want)
+	}
+
+	defaultResolver = testResolver{addr: "255.209.76.40"}
+	if _, err := ConvertUTCtoTAI(ctx, time.Now()); err == nil {
+		t.Error("got no error")
+	}
+	if _, err := ConvertTAItoUTC(ctx, time.Now()); err == nil {
+		t.Error("got no error")
+	}
+}
+
+func TestResultTAIFromUTC(t *testing.T) {
+	r := Result{2015, 6, 35, +1, 2}
+	date := func(day, hour, min, sec int) time.Time {
+		return time.Date(2015, 6, day, hour, min, sec, 0, time.UTC)
+	}
+	tests := []struct {
+		utc, tai time.Time
+	}{
+		{date(1, 0, 0, 0), date(1, 0, 0, 35)},
+		{date(30, 23, 59, 59), date(31, 0, 0, 34)},
+		{date(31, 0, 0, 0), date(31, 0, 0, 36)}, // July 1
+		{date(31, 0, 0, 1), date(31, 0, 0, 37)},
+	}
+	for _, tt := range tests {
+		if got := r.TAIFromUTC(tt.utc); !got.Equal(tt.tai) {
+			t.Errorf("%v: got %v, want: %v", tt.utc, got, tt.tai)
+		}
+		if got := r.UTCFromTAI(tt.tai); !got.Equal(tt.utc) {
+			t.Errorf("%v: got %v, want: %v", tt.tai, got, tt.utc)
+		}
+	}
+
+	// TAI 00:00:35 is the inserted 23:59:60, one second later the
+	// horizon has passed and DTAI+Delta applies.
+	if got, want := r.UTCFromTAI(date(31, 0, 0, 35)), date(30, 23, 59, 60); !got.Equal(want) {
+		t.Errorf("got %v, want: %v", got, want)
+	}
+	if got, want := r.UTCFromTAI(date(31, 0, 0, 36)), date(31, 0, 0, 0); !got.Equal(want) {
+		t.Errorf("got %v, want: %v", got, want)
+	}
+}
+
+func TestNextLeapSecond(t *testing.T) {
+	defer func(r Resolver) { defaultResolver = r }(defaultResolver)
+	ctx := context.Background()
+
+	defaultResolver = testResolver{addr: "244.23.35.255"}
+	leap, delta, err := NextLeapSecond(ctx)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if want := time.Date(2015, 6, 30, 23, 59, 60, 0, time.UTC); !leap.Equal(want) || delta != +1 {
+		t.Errorf("got (%v, %d), want: (%v, +1)", leap, delta, want)
+	}
+
+	defaultResolver = testResolver{addr: "242.18.28.160"} // delta 0
+	if _, _, err := NextLeapSecond(ctx); !errors.Is(err, ErrNoLeapSecond) {
+		t.Errorf("got %v, want: %v", err, ErrNoLeapSecond)
+	}
+
+	defaultResolver = testResolver{}
+	if _, _, err := NextLeapSecond(ctx); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("got %v, want code -11", err)
+	}
+}
@@ -0,0 +1,94 @@
+package dnsleapsecs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DecodeZone scans r for leap-second records and decodes them in order
+// of appearance. It understands a simple line based grammar covering
+// both zone files and hosts files:
+//
+//   - Text following ';' or '#' is a comment, blank lines are skipped.
+//   - A zone file record is "owner [ttl] [class] A address", in which
+//     ttl and class may appear in either order. A line starting with
+//     white space omits the owner and takes it from the previous record.
+//     Directives like $ORIGIN and $TTL and other record types are
+//     skipped, multi-line records are not supported.
+//   - A hosts file entry is "address name [alias ...]", recognized by
+//     its first field being a numeric IPv4 address.
+//
+// A record is a leap-second record when the first label of its owner,
+// or of one of its names for a hosts file entry, is "leapsecond"
+// (case insensitive), so "leapsecond.utcd.org." and a relative
+// "leapsecond" both match. The first record that fails to decode is
+// returned as error holding its line number.
+func DecodeZone(r io.Reader) ([]Result, error) {
+	var results []Result
+	var owner string
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+		if i := strings.IndexAny(line, ";#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "$") {
+			continue
+		}
+
+		var ip string
+		var names []string
+		if _, err := parseIPv4(fields[0]); err == nil {
+			ip, names = fields[0], fields[1:]
+		} else {
+			if line[0] != ' ' && line[0] != '\t' {
+				owner, fields = fields[0], fields[1:]
+			}
+			ip = zoneA(fields)
+			names = []string{owner}
+		}
+		if ip == "" || !isLeapsecondName(names) {
+			continue
+		}
+
+		r, err := Decode(ip)
+		if err != nil {
+			return results, fmt.Errorf("line %d: %w", n, err)
+		}
+		results = append(results, r)
+	}
+	return results, s.Err()
+}
+
+// zoneA returns the address of an A record given the fields following
+// its owner, or the empty string if it's another record.
+func zoneA(fields []string) string {
+	for i, f := range fields {
+		switch {
+		case strings.EqualFold(f, "IN"), strings.EqualFold(f, "CH"), strings.EqualFold(f, "HS"):
+		case f[0] >= '0' && f[0] <= '9':
+			// TTL
+		case strings.EqualFold(f, "A") && len(fields) == i+2:
+			return fields[i+1]
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+func isLeapsecondName(names []string) bool {
+	for _, name := range names {
+		label := name
+		if i := strings.IndexByte(name, '.'); i >= 0 {
+			label = name[:i]
+		}
+		if strings.EqualFold(label, "leapsecond") {
+			return true
+		}
+	}
+	return false
+}
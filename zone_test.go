@@ -0,0 +1,60 @@
+package dnsleapsecs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeZone(t *testing.T) {
+	const zone = `$ORIGIN utcd.org.
+$TTL 3600
+; leap-second announcements
+@                  IN  SOA  ns.utcd.org. hostmaster.utcd.org. 1 7200 900 1209600 3600
+www                    A    192.0.2.1
+leapsecond         IN  A    244.23.35.255 ; Bulletin C 49
+                   IN  A    240.3.9.77
+leapsecond.utcd.org. 300 IN A 242.18.28.160
+leapsecond         IN  AAAA 2001:db8::1
+LeapSecond.example. IN 60 A 240.15.10.108
+other              IN  A    255.76.200.237
+
+# hosts file
+255.76.200.237  leapsecond.test leapsecond
+192.0.2.2       localhost
+`
+	results, err := DecodeZone(strings.NewReader(zone))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	want := []Result{
+		{2015, 6, 35, +1, 2},
+		{1971, 12, 9, +1, 2},
+		{1993, 12, 28, 0, 0},
+		{1972, 6, 10, +1, 2},
+		{2135, 1, 72, -1, 1},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %#v, want: %#v", results, want)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("%d: got %#v, want: %#v", i, results[i], want[i])
+		}
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		const zone = "leapsecond A 240.3.9.77\nleapsecond A 255.209.76.40\n"
+		results, err := DecodeZone(strings.NewReader(zone))
+		var e *Error
+		if !errors.As(err, &e) || e.Code != -2 {
+			t.Fatalf("got %#v, want code -2", err)
+		}
+		if !strings.HasPrefix(err.Error(), "line 2: ") {
+			t.Errorf("got %q, want line 2", err)
+		}
+		if len(results) != 1 {
+			t.Errorf("got %d results, want: 1", len(results))
+		}
+	})
+}